@@ -0,0 +1,104 @@
+package hand
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Board is the community cards shared by all players in games like
+// Hold'em and Omaha.  A board is either empty or has a dealt flop, in
+// which case it may also have a turn and a river.
+type Board struct {
+	cards []*Card
+}
+
+// NewBoard returns a board of the given cards in dealing order.  NewBoard
+// returns ErrInvalidBoard if there are not zero, three, four, or five
+// cards and an error if any card is malformed or repeated.
+func NewBoard(cards []*Card) (*Board, error) {
+	switch len(cards) {
+	case 0, 3, 4, 5:
+	default:
+		return nil, ErrInvalidBoard
+	}
+	for _, card := range cards {
+		if card == nil || !card.Rank().valid() || !card.Suit().valid() {
+			return nil, fmt.Errorf("hand: invalid card in board %v", cards)
+		}
+	}
+	if card := duplicateCard(cards); card != nil {
+		return nil, fmt.Errorf("hand: duplicate card %v in board %v", card, cards)
+	}
+	return &Board{cards: append([]*Card{}, cards...)}, nil
+}
+
+// Flop returns the first three cards of the board or nil if the flop
+// hasn't been dealt.
+func (b *Board) Flop() []*Card {
+	if len(b.cards) < 3 {
+		return nil
+	}
+	return append([]*Card{}, b.cards[:3]...)
+}
+
+// Turn returns the fourth card of the board or nil if the turn hasn't
+// been dealt.
+func (b *Board) Turn() *Card {
+	if len(b.cards) < 4 {
+		return nil
+	}
+	return b.cards[3]
+}
+
+// River returns the fifth card of the board or nil if the river hasn't
+// been dealt.
+func (b *Board) River() *Card {
+	if len(b.cards) < 5 {
+		return nil
+	}
+	return b.cards[4]
+}
+
+// Cards returns a copy of the board's cards in dealing order.
+func (b *Board) Cards() []*Card {
+	return append([]*Card{}, b.cards...)
+}
+
+// BestHoldemHand returns the best five card hand formed from the two hole
+// cards and the board.  BestHoldemHand returns ErrInvalidBoard if the flop
+// hasn't been dealt.
+func (b *Board) BestHoldemHand(hole [2]*Card, options ...func(*Config)) (*Hand, error) {
+	return BestHoldemHandErr(hole, b.cards, options...)
+}
+
+// BestOmahaHand returns the best five card hand formed from exactly two
+// of the four hole cards and exactly three of the board cards.
+// BestOmahaHand returns ErrInvalidBoard if the flop hasn't been dealt.
+func (b *Board) BestOmahaHand(hole [4]*Card, options ...func(*Config)) (*Hand, error) {
+	if len(b.cards) < 3 {
+		return nil, ErrInvalidBoard
+	}
+	c := newConfig(options)
+	hands := []*Hand{}
+	for _, hCombo := range combinations(len(hole), 2) {
+		for _, bCombo := range combinations(len(b.cards), 3) {
+			cards := []*Card{hole[hCombo[0]], hole[hCombo[1]],
+				b.cards[bCombo[0]], b.cards[bCombo[1]], b.cards[bCombo[2]]}
+			h, err := NewErr(cards, options...)
+			if err != nil {
+				return nil, err
+			}
+			hands = append(hands, h)
+		}
+	}
+	return Sort(c.sorting, DESC, hands...)[0], nil
+}
+
+// String implements the fmt.Stringer interface.
+func (b *Board) String() string {
+	s := []string{}
+	for _, c := range b.cards {
+		s = append(s, c.String())
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,73 @@
+package hand_test
+
+import (
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+func TestNewBoard(t *testing.T) {
+	cards := jokertest.Cards("Ad", "Kc", "Ks", "2h", "3d")
+	for _, n := range []int{0, 3, 4, 5} {
+		if _, err := NewBoard(cards[:n]); err != nil {
+			t.Fatalf("NewBoard(%v) error = %v", cards[:n], err)
+		}
+	}
+	for _, c := range [][]*Card{cards[:1], cards[:2], append(cards, TwoSpades)} {
+		if _, err := NewBoard(c); err != ErrInvalidBoard {
+			t.Fatalf("NewBoard(%v) error = %v; want %v", c, err, ErrInvalidBoard)
+		}
+	}
+	if _, err := NewBoard(jokertest.Cards("Ad", "Kc", "Ad")); err == nil {
+		t.Fatal("NewBoard() should return an error for duplicate cards")
+	}
+	if _, err := NewBoard([]*Card{AceDiamonds, nil, KingClubs}); err == nil {
+		t.Fatal("NewBoard() should return an error for nil cards")
+	}
+}
+
+func TestBoardStreets(t *testing.T) {
+	cards := jokertest.Cards("Ad", "Kc", "Ks", "2h", "3d")
+	b, err := NewBoard(cards[:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flop := b.Flop(); len(flop) != 3 || flop[0] != AceDiamonds || flop[2] != KingSpades {
+		t.Fatalf("Flop() = %v; want %v", flop, cards[:3])
+	}
+	if b.Turn() != TwoHearts {
+		t.Fatalf("Turn() = %v; want %v", b.Turn(), TwoHearts)
+	}
+	if b.River() != nil {
+		t.Fatalf("River() = %v; want nil", b.River())
+	}
+	if len(b.Cards()) != 4 || b.String() != "A♦ K♣ K♠ 2♥" {
+		t.Fatalf("Cards() = %v; want %v", b.Cards(), cards[:4])
+	}
+
+	b, _ = NewBoard(nil)
+	if b.Flop() != nil || b.Turn() != nil || b.River() != nil {
+		t.Fatalf("expected an empty board got %v", b)
+	}
+}
+
+func TestBoardBestHands(t *testing.T) {
+	b, _ := NewBoard(jokertest.Cards("2s", "7s", "9s", "Js"))
+	h, err := b.BestHoldemHand([2]*Card{AceSpades, AceHearts})
+	if err != nil || h.Ranking() != Flush {
+		t.Fatalf("BestHoldemHand() = %v, %v; want %v", h, err, Flush)
+	}
+	h, err = b.BestOmahaHand([4]*Card{AceSpades, AceHearts, KingDiamonds, QueenClubs})
+	if err != nil || h.Ranking() != Pair {
+		t.Fatalf("BestOmahaHand() = %v, %v; want %v", h, err, Pair)
+	}
+
+	b, _ = NewBoard(nil)
+	if _, err := b.BestHoldemHand([2]*Card{AceSpades, AceHearts}); err != ErrInvalidBoard {
+		t.Fatalf("BestHoldemHand() error = %v; want %v", err, ErrInvalidBoard)
+	}
+	if _, err := b.BestOmahaHand([4]*Card{AceSpades, AceHearts, KingDiamonds, QueenClubs}); err != ErrInvalidBoard {
+		t.Fatalf("BestOmahaHand() error = %v; want %v", err, ErrInvalidBoard)
+	}
+}
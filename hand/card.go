@@ -2,6 +2,7 @@ package hand
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -49,6 +50,22 @@ const (
 	Ace Rank = "A"
 )
 
+// ParseRank parses a rank from its single character form such as "K" or
+// its name such as "king".  Parsing is case insensitive and "10" is
+// accepted as Ten.
+func ParseRank(s string) (Rank, error) {
+	lower := strings.ToLower(s)
+	if lower == "10" {
+		return Ten, nil
+	}
+	for _, r := range allRanks() {
+		if strings.ToLower(string(r)) == lower || singularNames[r] == lower {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("hand: can't parse rank %q", s)
+}
+
 // IndexOf returns the index of the rank in the ascending order of ranks.
 // IndexOf returns -1 if the rank is not found.
 func (r Rank) indexOf() int {
@@ -65,14 +82,14 @@ func (r Rank) String() string {
 	return string(r)
 }
 
-// singularName returns the name of the rank in singular form such as "two" for Two.
+// singularName returns the localized name of the rank in singular form such as "two" for Two.
 func (r Rank) singularName() string {
-	return singularNames[r]
+	return localizer.SingularName(r)
 }
 
-// pluralName returns the name of the rank in plural form such as "twos" for Two.
+// pluralName returns the localized name of the rank in plural form such as "twos" for Two.
 func (r Rank) pluralName() string {
-	return pluralNames[r]
+	return localizer.PluralName(r)
 }
 
 // Valid returns true if the rank is valid
@@ -89,30 +106,6 @@ func (r Rank) aceLowIndexOf() int {
 	return -1
 }
 
-type byAceHighRank []Rank
-
-func (a byAceHighRank) Len() int { return len(a) }
-
-func (a byAceHighRank) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
-
-func (a byAceHighRank) Less(i, j int) bool {
-	iRank, jRank := a[i], a[j]
-	iIndex, jIndex := iRank.indexOf(), jRank.indexOf()
-	return iIndex < jIndex
-}
-
-type byAceLowRank []Rank
-
-func (a byAceLowRank) Len() int { return len(a) }
-
-func (a byAceLowRank) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
-
-func (a byAceLowRank) Less(i, j int) bool {
-	iRank, jRank := a[i], a[j]
-	iIndex, jIndex := iRank.aceLowIndexOf(), jRank.aceLowIndexOf()
-	return iIndex < jIndex
-}
-
 var (
 	singularNames = map[Rank]string{
 		Two:   "two",
@@ -164,16 +157,69 @@ const (
 	Clubs Suit = "♣"
 )
 
+// ParseSuit parses a suit from its letter such as "s", its name such as
+// "spades", or its symbol such as "♠".  Parsing is case insensitive.
+func ParseSuit(s string) (Suit, error) {
+	lower := strings.ToLower(s)
+	for _, suit := range allSuits() {
+		name := suitNames[suit]
+		if lower == suit.Symbol() || lower == name || lower == suit.Letter() {
+			return suit, nil
+		}
+	}
+	return "", fmt.Errorf("hand: can't parse suit %q", s)
+}
+
 // String returns a string in the format "♠"
 func (s Suit) String() string {
 	return string(s)
 }
 
+// Symbol returns the suit's unicode symbol such as "♠".
+func (s Suit) Symbol() string {
+	return string(s)
+}
+
+// Letter returns the suit's lowercase letter such as "s" as accepted by
+// ParseSuit and ParseCards.  Letter returns an empty string for an
+// invalid suit.
+func (s Suit) Letter() string {
+	if !s.valid() {
+		return ""
+	}
+	return suitNames[s][:1]
+}
+
+// indexOf returns the suit's index in allSuits or the number of suits
+// if it isn't valid.
+func (s Suit) indexOf() int {
+	for i, suit := range allSuits() {
+		if s == suit {
+			return i
+		}
+	}
+	return len(allSuits())
+}
+
 func (s Suit) valid() bool {
-	return strings.Contains("♠♥♦♣", string(s))
+	for _, suit := range allSuits() {
+		if s == suit {
+			return true
+		}
+	}
+	return false
+}
+
+var suitNames = map[Suit]string{
+	Spades:   "spades",
+	Hearts:   "hearts",
+	Diamonds: "diamonds",
+	Clubs:    "clubs",
 }
 
 // A Card represents a playing card in the game of poker.  It is composed of a rank and suit.
+// Two *Card values may be different pointers to the same card so use Equal
+// rather than == to compare them.
 type Card struct {
 	rank Rank
 	suit Suit
@@ -189,14 +235,40 @@ func (c *Card) Suit() Suit {
 	return c.suit
 }
 
+// Equal returns true if the other card has the same rank and suit.
+func (c *Card) Equal(o *Card) bool {
+	return o != nil && c.rank == o.rank && c.suit == o.suit
+}
+
+// A Color is the color of a card's suit.
+type Color string
+
+const (
+	// Black is the color of spades and clubs
+	Black Color = "black"
+
+	// Red is the color of hearts and diamonds
+	Red Color = "red"
+)
+
+// Color returns Red for hearts and diamonds and Black for spades and
+// clubs.
+func (c *Card) Color() Color {
+	if c.suit == Hearts || c.suit == Diamonds {
+		return Red
+	}
+	return Black
+}
+
 // String returns a string in the format "4♠"
 func (c *Card) String() string {
 	return string(c.Rank()) + string(c.Suit())
 }
 
 // MarshalText implements the encoding.TextMarshaler interface.
-// The text format is "4♠".
-func (c *Card) MarshalText() ([]byte, error) {
+// The text format is "4♠".  MarshalText has a value receiver so
+// that maps keyed by Card can be marshalled.
+func (c Card) MarshalText() ([]byte, error) {
 	return []byte(c.String()), nil
 }
 
@@ -281,7 +353,9 @@ var (
 	TwoClubs   = &Card{rank: Two, suit: Clubs}
 )
 
-// Cards returns all 52 unshuffled cards
+// Cards returns all 52 unshuffled cards.  The cards are ordered by suit
+// (spades, hearts, diamonds, then clubs) and within each suit from ace
+// down to two.
 func Cards() []*Card {
 	return []*Card{
 		AceSpades, KingSpades, QueenSpades, JackSpades, TenSpades,
@@ -302,6 +376,122 @@ func Cards() []*Card {
 	}
 }
 
+// ParseCards parses a space separated list of cards such as
+// "As Kh Td" or "A♠ K♥ T♦".  Each card is a rank followed by either a
+// suit letter (s, h, d, c) or suit symbol.  The returned error names the
+// first card that couldn't be parsed.
+func ParseCards(s string) ([]*Card, error) {
+	cards := []*Card{}
+	for _, token := range strings.Fields(s) {
+		card, err := parseCard(token)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+func parseCard(token string) (*Card, error) {
+	runes := []rune(token)
+	if len(runes) != 2 {
+		return nil, fmt.Errorf("hand: can't parse card %q", token)
+	}
+	rank, rErr := ParseRank(string(runes[0]))
+	suit, sErr := ParseSuit(string(runes[1]))
+	if rErr != nil || sErr != nil {
+		return nil, fmt.Errorf("hand: can't parse card %q", token)
+	}
+	return NewCardChecked(rank, suit)
+}
+
+// NewCard returns the card of the given rank and suit such as AceSpades.
+// NewCard panics if the rank or suit is invalid, use NewCardChecked to
+// receive an error instead.
+func NewCard(r Rank, s Suit) *Card {
+	c, err := NewCardChecked(r, s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewCardChecked is the same as NewCard except that it returns an error
+// if the rank or suit is invalid.
+func NewCardChecked(r Rank, s Suit) (*Card, error) {
+	for _, c := range Cards() {
+		if c.Rank() == r && c.Suit() == s {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("hand: invalid card rank %q suit %q", r, s)
+}
+
+// ID returns the card's position from 0 to 51 in a stable ordering of the
+// deck.  The ID is the index of the rank from two to ace times four plus
+// the index of the suit in the order spades, hearts, diamonds, and clubs,
+// so 2♠ is 0, 2♥ is 1, 3♠ is 4, and A♣ is 51.  The mapping won't change
+// between releases.  ID panics if the card is malformed.
+func (c *Card) ID() int {
+	r, s := c.Rank().indexOf(), c.Suit().indexOf()
+	if r < 0 || s >= len(allSuits()) {
+		panic(fmt.Errorf("hand: invalid card rank %q suit %q", c.rank, c.suit))
+	}
+	return r*len(allSuits()) + s
+}
+
+// CardFromID returns the card with the given ID.  CardFromID panics if the
+// id isn't between 0 and 51.
+func CardFromID(id int) *Card {
+	if id < 0 || id >= len(cardsByID) {
+		panic(fmt.Errorf("hand: invalid card id %d", id))
+	}
+	return cardsByID[id]
+}
+
+// cardsByID is indexed by card ID
+var cardsByID = func() (cards [52]*Card) {
+	for _, c := range Cards() {
+		cards[c.ID()] = c
+	}
+	return cards
+}()
+
+// CanonicalizeSuits returns the cards with their suits relabeled in the
+// order they are first seen as spades, hearts, diamonds, and then clubs.
+// Cards that share a suit still share a suit so A♥ K♥ and A♠ K♠ are both
+// canonicalized to A♠ K♠.  CanonicalizeSuits panics if given malformed
+// cards.
+func CanonicalizeSuits(cards []*Card) []*Card {
+	suits := map[Suit]Suit{}
+	canonical := make([]*Card, len(cards))
+	for i, c := range cards {
+		s, ok := suits[c.Suit()]
+		if !ok {
+			s = allSuits()[len(suits)]
+			suits[c.Suit()] = s
+		}
+		canonical[i] = NewCard(c.Rank(), s)
+	}
+	return canonical
+}
+
+// RemainingCards returns the cards from Cards that don't share a rank and
+// suit with any of the used cards.
+func RemainingCards(used []*Card) []*Card {
+	seen := map[Card]bool{}
+	for _, c := range used {
+		seen[*c] = true
+	}
+	remaining := []*Card{}
+	for _, c := range Cards() {
+		if !seen[*c] {
+			remaining = append(remaining, c)
+		}
+	}
+	return remaining
+}
+
 type byAceHigh []*Card
 
 func (a byAceHigh) Len() int { return len(a) }
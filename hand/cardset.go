@@ -0,0 +1,59 @@
+package hand
+
+import "math/bits"
+
+// A CardSet is a set of cards stored as a bitmask in which the bit at a
+// card's ID is set if the set contains the card.  The zero value is an
+// empty set.
+type CardSet uint64
+
+// NewCardSet returns a set of the given cards.  NewCardSet panics if given
+// malformed cards.
+func NewCardSet(cards ...*Card) CardSet {
+	return CardSet(0).Add(cards...)
+}
+
+// Add returns the set with the given cards added.  Add panics if given
+// malformed cards.
+func (s CardSet) Add(cards ...*Card) CardSet {
+	for _, c := range cards {
+		s |= 1 << uint(c.ID())
+	}
+	return s
+}
+
+// Has returns true if the set contains the card.
+func (s CardSet) Has(c *Card) bool {
+	return s&(1<<uint(c.ID())) != 0
+}
+
+// Union returns the set of cards in either set.
+func (s CardSet) Union(o CardSet) CardSet {
+	return s | o
+}
+
+// Intersect returns the set of cards in both sets.
+func (s CardSet) Intersect(o CardSet) CardSet {
+	return s & o
+}
+
+// Difference returns the set of cards in s but not in o.
+func (s CardSet) Difference(o CardSet) CardSet {
+	return s &^ o
+}
+
+// PopCount returns the number of cards in the set.
+func (s CardSet) PopCount() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// Cards returns the cards in the set ordered by ID.
+func (s CardSet) Cards() []*Card {
+	cards := make([]*Card, 0, s.PopCount())
+	for s != 0 {
+		id := bits.TrailingZeros64(uint64(s))
+		cards = append(cards, CardFromID(id))
+		s &= s - 1
+	}
+	return cards
+}
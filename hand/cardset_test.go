@@ -0,0 +1,45 @@
+package hand_test
+
+import (
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+func TestCardSet(t *testing.T) {
+	var empty CardSet
+	if empty.PopCount() != 0 || empty.Has(AceSpades) || len(empty.Cards()) != 0 {
+		t.Fatalf("expected an empty set got %v", empty.Cards())
+	}
+
+	s := NewCardSet(jokertest.Cards("As", "Kd", "2c")...)
+	if s.PopCount() != 3 || !s.Has(AceSpades) || !s.Has(TwoClubs) || s.Has(AceHearts) {
+		t.Fatalf("NewCardSet() = %v", s.Cards())
+	}
+	if s.Add(AceSpades) != s {
+		t.Fatal("adding a card in the set should not change it")
+	}
+	if cards := s.Cards(); len(cards) != 3 || cards[0] != TwoClubs || cards[2] != AceSpades {
+		t.Fatalf("Cards() = %v; want [2♣ K♦ A♠]", cards)
+	}
+
+	o := NewCardSet(jokertest.Cards("As", "Qh")...)
+	if u := s.Union(o); u.PopCount() != 4 || !u.Has(QueenHearts) || !u.Has(KingDiamonds) {
+		t.Fatalf("Union() = %v", u.Cards())
+	}
+	if i := s.Intersect(o); i != NewCardSet(AceSpades) {
+		t.Fatalf("Intersect() = %v; want [A♠]", i.Cards())
+	}
+	if d := s.Difference(o); d != NewCardSet(KingDiamonds, TwoClubs) {
+		t.Fatalf("Difference() = %v; want [2♣ K♦]", d.Cards())
+	}
+
+	all := NewCardSet(Cards()...)
+	if all.PopCount() != 52 || all.Union(s) != all || all.Intersect(s) != s {
+		t.Fatalf("expected a full deck got %d cards", all.PopCount())
+	}
+	if all.Difference(s).Union(s) != all || all.Difference(all) != empty {
+		t.Fatal("difference and union should be inverses")
+	}
+}
@@ -1,14 +1,149 @@
 package hand
 
 import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
 	"math/rand"
 	"strings"
 	"time"
 )
 
-// Deck is a slice of cards used for dealing
+// ErrDeckExhausted is returned by Draw when the deck doesn't have
+// enough cards remaining.
+var ErrDeckExhausted = errors.New("hand: deck doesn't have enough cards to draw")
+
+// Deck is a slice of cards used for dealing.  The top of the deck is the
+// end of Cards.  Burned holds the cards discarded by Burn in the order
+// they were burned.
 type Deck struct {
-	Cards []*Card
+	Cards  []*Card
+	Burned []*Card
+}
+
+// NewDeck returns a deck of all 52 cards in unshuffled order.
+func NewDeck() *Deck {
+	return &Deck{Cards: Cards()}
+}
+
+// Shuffle randomizes the order of the deck's cards using shuffle, which
+// has the same signature as rand.Shuffle so a rand.Rand's Shuffle method
+// can be passed directly.  The deal is only as unpredictable as shuffle:
+// math/rand is deterministic and its state can be recovered from dealt
+// cards, so it must not be used where players have money at stake.  Pass
+// a shuffle backed by crypto/rand for provably fair dealing.
+func (d *Deck) Shuffle(shuffle func(n int, swap func(i, j int))) {
+	shuffle(len(d.Cards), func(i, j int) {
+		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+	})
+}
+
+// SeededShuffle returns a math/rand shuffle for Shuffle with the given
+// seed.  Using the same seed produces reproducible deals which is useful
+// for simulations and tests but not for real games.
+func SeededShuffle(seed int64) func(n int, swap func(i, j int)) {
+	return rand.New(rand.NewSource(seed)).Shuffle
+}
+
+// DeckFromSeed returns a deck shuffled deterministically from the seed so
+// a deal can be replayed from the seed alone, such as one included in a
+// bug report.  The seed is hashed with SHA-256 and the first eight bytes,
+// read big endian, seed SeededShuffle.  This mapping from seed to order
+// is stable across releases.
+func DeckFromSeed(seed string) *Deck {
+	sum := sha256.Sum256([]byte(seed))
+	d := NewDeck()
+	d.Shuffle(SeededShuffle(int64(binary.BigEndian.Uint64(sum[:8]))))
+	return d
+}
+
+// SecureShuffle randomizes the order of the deck's cards with an unbiased
+// Fisher-Yates shuffle using crypto/rand, making deals unpredictable to
+// players.  SecureShuffle returns an error if crypto/rand fails to read
+// in which case the deck may be partially shuffled.
+func (d *Deck) SecureShuffle() error {
+	for i := len(d.Cards) - 1; i > 0; i-- {
+		n, err := crand.Int(crand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return err
+		}
+		j := int(n.Int64())
+		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+	}
+	return nil
+}
+
+// Draw removes n cards from the deck and returns them in the same
+// order as PopMulti.  Draw returns ErrDeckExhausted and leaves the deck
+// unchanged if fewer than n cards remain.
+func (d *Deck) Draw(n int) ([]*Card, error) {
+	if n < 0 || n > len(d.Cards) {
+		return nil, ErrDeckExhausted
+	}
+	return d.PopMulti(n), nil
+}
+
+// DealHands deals cardsEach cards to each of the players one card at a
+// time, dealing a card to every player before any player receives their
+// next card.  DealHands returns ErrDeckExhausted and leaves the deck
+// unchanged if there aren't enough cards.
+func (d *Deck) DealHands(players, cardsEach int) ([][]*Card, error) {
+	if players < 0 || cardsEach < 0 || players*cardsEach > len(d.Cards) {
+		return nil, ErrDeckExhausted
+	}
+	hands := make([][]*Card, players)
+	for i := 0; i < cardsEach; i++ {
+		for p := range hands {
+			hands[p] = append(hands[p], d.Pop())
+		}
+	}
+	return hands, nil
+}
+
+// Cut moves the top n cards of the deck to the bottom without changing
+// their order.  Cut panics if n is negative or greater than the number of
+// cards.
+func (d *Deck) Cut(n int) {
+	if n < 0 || n > len(d.Cards) {
+		panic(fmt.Errorf("hand: can't cut %d cards from a deck of %d", n, len(d.Cards)))
+	}
+	top := len(d.Cards) - n
+	d.Cards = append(append([]*Card{}, d.Cards[top:]...), d.Cards[:top]...)
+}
+
+// Burn discards the top card of the deck, as dealers do before dealing
+// the flop, turn, and river, and returns it.  The card is also added to
+// Burned so burned cards can be audited.  Burn returns ErrDeckExhausted
+// if the deck is empty.
+func (d *Deck) Burn() (*Card, error) {
+	if len(d.Cards) == 0 {
+		return nil, ErrDeckExhausted
+	}
+	c := d.Pop()
+	d.Burned = append(d.Burned, c)
+	return c, nil
+}
+
+// Remove removes the cards from the deck that share a rank and suit with
+// any of the given cards and returns the number removed.  The order of the
+// remaining cards is unchanged.
+func (d *Deck) Remove(cards ...*Card) int {
+	remaining := []*Card{}
+	for _, c := range d.Cards {
+		removed := false
+		for _, r := range cards {
+			removed = removed || c.Equal(r)
+		}
+		if !removed {
+			remaining = append(remaining, c)
+		}
+	}
+	n := len(d.Cards) - len(remaining)
+	d.Cards = remaining
+	return n
 }
 
 // Pop removes a card from the deck and returns it.  Pop
@@ -0,0 +1,208 @@
+package hand
+
+import "math/bits"
+
+// DrawOdds returns the probability of the best Texas Hold'em hand formed
+// from the hole cards and board finishing with each ranking stronger than
+// the current ranking once the board is complete.  Every possible
+// completion of the board is enumerated.  DrawOdds panics with
+// ErrInvalidBoard if the board doesn't have between three and five cards.
+func DrawOdds(hole []*Card, board []*Card) map[Ranking]float64 {
+	if len(board) < 3 || len(board) > 5 {
+		panic(ErrInvalidBoard)
+	}
+	cards := make([]*Card, 0, len(hole)+5)
+	cards = append(cards, hole...)
+	cards = append(cards, board...)
+	current := New(cards).Ranking()
+
+	odds := map[Ranking]float64{}
+	for r := current + 1; r <= RoyalFlush; r++ {
+		odds[r] = 0
+	}
+	if len(board) == 5 {
+		return odds
+	}
+
+	remaining := RemainingCards(cards)
+	combos := combinations(len(remaining), 5-len(board))
+	known := len(cards)
+	for _, combo := range combos {
+		cards = cards[:known]
+		for _, i := range combo {
+			cards = append(cards, remaining[i])
+		}
+		if r := New(cards).Ranking(); r > current {
+			odds[r]++
+		}
+	}
+	for r := range odds {
+		odds[r] /= float64(len(combos))
+	}
+	return odds
+}
+
+// Outs returns the cards that would improve the best Texas Hold'em hand
+// formed from the hole cards and board to at least the target ranking
+// if dealt as the next board card.  Outs returns an empty slice if the
+// hand already meets the target or the board is complete.  Outs panics
+// with ErrInvalidBoard if the board doesn't have between three and five
+// cards.
+func Outs(hole []*Card, board []*Card, target Ranking) []*Card {
+	if len(board) < 3 || len(board) > 5 {
+		panic(ErrInvalidBoard)
+	}
+	cards := make([]*Card, 0, len(hole)+len(board)+1)
+	cards = append(cards, hole...)
+	cards = append(cards, board...)
+	outs := []*Card{}
+	if len(board) == 5 || New(cards).Ranking() >= target {
+		return outs
+	}
+
+	for _, c := range RemainingCards(cards) {
+		if New(append(cards, c)).Ranking() >= target {
+			outs = append(outs, c)
+		}
+	}
+	return outs
+}
+
+// Draws describes the straights, flushes, and draws to them in a set of
+// cards regardless of the hand the cards make.
+type Draws struct {
+	// Straight is true if five of the cards make a straight.
+	Straight bool
+
+	// Flush is true if five of the cards share a suit.
+	Flush bool
+
+	// FlushDraws are the suits shared by exactly four of the cards.
+	FlushDraws []Suit
+
+	// StraightOuts are the ranks that would complete a straight from
+	// lowest to highest.  StraightOuts is empty if the cards already make
+	// a straight.
+	StraightOuts []Rank
+
+	// OpenEnded is true if two or more ranks would complete a straight,
+	// which includes double gutshots since they have as many outs.
+	OpenEnded bool
+
+	// Gutshot is true if exactly one rank would complete a straight.
+	Gutshot bool
+
+	// Combo is true if there is both a flush draw and a straight draw.
+	Combo bool
+}
+
+// FindDraws returns the straights, flushes, and draws in the cards.  Aces
+// are both high and low for straights.  FindDraws panics if given
+// malformed cards.
+func FindDraws(cards []*Card) Draws {
+	d := Draws{}
+	var counts [4]int
+	for _, c := range cards {
+		counts[c.Suit().indexOf()]++
+	}
+	for i, n := range counts {
+		if n >= 5 {
+			d.Flush = true
+		} else if n == 4 {
+			d.FlushDraws = append(d.FlushDraws, allSuits()[i])
+		}
+	}
+
+	// bit 0 of the mask is the ace played low
+	m := rankMask(cards)
+	low := uint32(m)<<1 | uint32(m>>aceIndex&1)
+	d.Straight = hasRun(low)
+	missing := uint32(0)
+	for start := uint(0); !d.Straight && start+5 <= aceIndex+2; start++ {
+		window := uint32(0x1F) << start
+		if bits.OnesCount32(low&window) == 4 {
+			missing |= window &^ low
+		}
+	}
+	// the low ace and high ace are the same rank
+	missing = missing>>1 | (missing&1)<<aceIndex
+	for i, r := range allRanks() {
+		if missing&(1<<uint(i)) != 0 {
+			d.StraightOuts = append(d.StraightOuts, r)
+		}
+	}
+
+	d.OpenEnded = len(d.StraightOuts) >= 2
+	d.Gutshot = len(d.StraightOuts) == 1
+	d.Combo = len(d.FlushDraws) > 0 && len(d.StraightOuts) > 0
+	return d
+}
+
+// Blockers returns, for each category of strong hand an opponent could
+// make with the board, the number of hole card combinations that hero
+// blocks by holding a card the combination needs.  The categories are
+// "nuts" for combinations that make the best possible hand, "nut flush"
+// for the best flush, "flush" for any flush including straight and royal
+// flushes, "straight", and "set" for pocket pairs that make three of a
+// kind with an unpaired board card.  The total number of combinations in
+// a category, blocked or not, is keyed by its name followed by " total"
+// so hero blocks m["nut flush"] of m["nut flush total"] nut flush
+// combinations.  Categories no opponent can make are omitted.  Blockers
+// panics with ErrInvalidBoard if the board doesn't have between three and
+// five cards.
+func Blockers(hero []*Card, board []*Card) map[string]int {
+	if len(board) < 3 || len(board) > 5 {
+		panic(ErrInvalidBoard)
+	}
+	remaining := RemainingCards(board)
+	combos := [][2]*Card{}
+	hands := []*Hand{}
+	var nuts, nutFlush *Hand
+	for _, c := range combinations(len(remaining), 2) {
+		hole := [2]*Card{remaining[c[0]], remaining[c[1]]}
+		h := BestHoldemHand(hole, board)
+		combos = append(combos, hole)
+		hands = append(hands, h)
+		if nuts == nil || h.Beats(nuts) {
+			nuts = h
+		}
+		if h.Ranking() == Flush && (nutFlush == nil || h.Beats(nutFlush)) {
+			nutFlush = h
+		}
+	}
+
+	categories := map[string][][2]*Card{}
+	for i, h := range hands {
+		if h.Ties(nuts) {
+			categories["nuts"] = append(categories["nuts"], combos[i])
+		}
+		switch h.Ranking() {
+		case Flush:
+			if h.Ties(nutFlush) {
+				categories["nut flush"] = append(categories["nut flush"], combos[i])
+			}
+			fallthrough
+		case StraightFlush, RoyalFlush:
+			categories["flush"] = append(categories["flush"], combos[i])
+		case Straight:
+			categories["straight"] = append(categories["straight"], combos[i])
+		}
+	}
+	for _, c := range board {
+		if countRank(board, c.Rank()) != 1 {
+			continue
+		}
+		pairs, err := ExpandRange(string(c.Rank()) + string(c.Rank()))
+		if err != nil {
+			panic(err)
+		}
+		categories["set"] = append(categories["set"], withoutCards(pairs, board)...)
+	}
+
+	blockers := map[string]int{}
+	for name, combos := range categories {
+		blockers[name] = len(combos) - len(withoutCards(combos, hero))
+		blockers[name+" total"] = len(combos)
+	}
+	return blockers
+}
@@ -0,0 +1,167 @@
+package hand_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+func TestDrawOdds(t *testing.T) {
+	// nut flush draw on the flop with no straight or full house possible
+	hole := jokertest.Cards("Ah", "Kh")
+	board := jokertest.Cards("2h", "7h", "Qc")
+	odds := DrawOdds(hole, board)
+	if expected := 378.0 / 1081.0; math.Abs(odds[Flush]-expected) > 1e-9 {
+		t.Fatalf("flush odds = %v; want %v", odds[Flush], expected)
+	}
+	if _, ok := odds[HighCard]; ok {
+		t.Fatal("odds should only include stronger rankings")
+	}
+
+	// wheel draw on the turn needs one of four fives
+	hole = jokertest.Cards("As", "2d")
+	board = jokertest.Cards("3c", "4h", "9s", "Kd")
+	odds = DrawOdds(hole, board)
+	if expected := 4.0 / 46.0; math.Abs(odds[Straight]-expected) > 1e-9 {
+		t.Fatalf("straight odds = %v; want %v", odds[Straight], expected)
+	}
+	if odds[Flush] != 0 {
+		t.Fatalf("flush odds = %v; want 0", odds[Flush])
+	}
+
+	board = append(board, jokertest.Cards("5c")...)
+	odds = DrawOdds(hole, board)
+	if len(odds) != 5 || odds[Flush] != 0 {
+		t.Fatalf("DrawOdds() = %v; want zero odds above %v", odds, Straight)
+	}
+}
+
+func TestOuts(t *testing.T) {
+	hole := jokertest.Cards("Ah", "Kh")
+	board := jokertest.Cards("2h", "7h", "Qc")
+	outs := Outs(hole, board, Flush)
+	if len(outs) != 9 {
+		t.Fatalf("Outs() = %v; want nine hearts", outs)
+	}
+	for _, c := range outs {
+		if c.Suit() != Hearts {
+			t.Fatalf("Outs() = %v; want nine hearts", outs)
+		}
+	}
+
+	// an open ended straight draw
+	hole = jokertest.Cards("9s", "8d")
+	board = jokertest.Cards("7c", "6h", "2s", "Kd")
+	if outs := Outs(hole, board, Straight); len(outs) != 8 {
+		t.Fatalf("Outs() = %v; want eight tens and fives", outs)
+	}
+
+	// the hand already meets the target
+	if outs := Outs(hole, board, HighCard); len(outs) != 0 {
+		t.Fatalf("Outs() = %v; want none", outs)
+	}
+}
+
+func TestFindDraws(t *testing.T) {
+	tests := []struct {
+		cards     string
+		outs      []Rank
+		openEnded bool
+		gutshot   bool
+		combo     bool
+	}{
+		{"8h 9d Tc Js 2c", []Rank{Seven, Queen}, true, false, false},
+		{"8h 9d Jc Qs 2c", []Rank{Ten}, false, true, false},
+		{"Ah 2d 3c 4s Kd", []Rank{Five}, false, true, false},
+		{"Ah Kd Qc Js 3s", []Rank{Ten}, false, true, false},
+		{"5h 7d 8c 9s Jd", []Rank{Six, Ten}, true, false, false},
+		{"8h 9h Th 2h Js", []Rank{Seven, Queen}, true, false, true},
+		{"Ah 7h 2h Kh Qs", nil, false, false, false},
+	}
+	for _, test := range tests {
+		cards, err := ParseCards(test.cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := FindDraws(cards)
+		if d.Straight || d.Flush {
+			t.Fatalf("FindDraws(%v) = %+v; want no made straight or flush", cards, d)
+		}
+		if len(d.StraightOuts) != len(test.outs) {
+			t.Fatalf("FindDraws(%v).StraightOuts = %v; want %v", cards, d.StraightOuts, test.outs)
+		}
+		for i, r := range test.outs {
+			if d.StraightOuts[i] != r {
+				t.Fatalf("FindDraws(%v).StraightOuts = %v; want %v", cards, d.StraightOuts, test.outs)
+			}
+		}
+		if d.OpenEnded != test.openEnded || d.Gutshot != test.gutshot || d.Combo != test.combo {
+			t.Fatalf("FindDraws(%v) = %+v", cards, d)
+		}
+	}
+
+	d := FindDraws(jokertest.Cards("Ah", "7h", "2h", "Kh", "Qs"))
+	if len(d.FlushDraws) != 1 || d.FlushDraws[0] != Hearts {
+		t.Fatalf("FindDraws().FlushDraws = %v; want [%v]", d.FlushDraws, Hearts)
+	}
+
+	d = FindDraws(jokertest.Cards("5h", "6d", "7c", "8s", "9d", "Ts", "Kd"))
+	if !d.Straight || len(d.StraightOuts) != 0 || d.OpenEnded {
+		t.Fatalf("FindDraws() = %+v; want a made straight", d)
+	}
+	d = FindDraws(jokertest.Cards("Ah", "2d", "3c", "4s", "5d"))
+	if !d.Straight {
+		t.Fatalf("FindDraws() = %+v; want a made wheel", d)
+	}
+	d = FindDraws(jokertest.Cards("Ah", "7h", "2h", "Kh", "Qh", "Js"))
+	if !d.Flush || len(d.FlushDraws) != 0 {
+		t.Fatalf("FindDraws() = %+v; want a made flush", d)
+	}
+}
+
+func TestBlockers(t *testing.T) {
+	board := jokertest.Cards("Ah", "7h", "2h")
+	blockers := Blockers(jokertest.Cards("Kh", "7c"), board)
+	expected := map[string]int{
+		"nuts":            1,
+		"nuts total":      1,
+		"nut flush":       1,
+		"nut flush total": 1,
+		"flush":           9,
+		"flush total":     45,
+		"set":             2,
+		"set total":       9,
+	}
+	if len(blockers) != len(expected) {
+		t.Fatalf("Blockers() = %v; want %v", blockers, expected)
+	}
+	for name, n := range expected {
+		if blockers[name] != n {
+			t.Fatalf("Blockers()[%q] = %d; want %d", name, blockers[name], n)
+		}
+	}
+
+	// hero blocks half of the nut straights
+	board = jokertest.Cards("9s", "8d", "7c", "2h")
+	blockers = Blockers(jokertest.Cards("Ts", "Th"), board)
+	if blockers["straight total"] != 48 || blockers["straight"] != 16 {
+		t.Fatalf("Blockers() straights = %d of %d; want 16 of 48", blockers["straight"], blockers["straight total"])
+	}
+	if blockers["nuts total"] != 16 || blockers["nuts"] != 8 {
+		t.Fatalf("Blockers() nuts = %d of %d; want 8 of 16", blockers["nuts"], blockers["nuts total"])
+	}
+	if blockers["set total"] != 12 || blockers["set"] != 0 {
+		t.Fatalf("Blockers() sets = %d of %d; want 0 of 12", blockers["set"], blockers["set total"])
+	}
+	if _, ok := blockers["flush total"]; ok {
+		t.Fatalf("Blockers() = %v; want no flushes", blockers)
+	}
+
+	// a paired board card can't make a set
+	blockers = Blockers(jokertest.Cards("Ts", "Th"), jokertest.Cards("9s", "9d", "2c"))
+	if blockers["set total"] != 3 {
+		t.Fatalf("Blockers() sets = %d; want 3", blockers["set total"])
+	}
+}
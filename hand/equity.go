@@ -0,0 +1,281 @@
+package hand
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"github.com/notnil/joker/util"
+)
+
+// EquityResult is a player's share of the outcomes of an equity
+// calculation.  Win and Tie are the fractions of outcomes won outright
+// and tied.  Equity is the average fraction of the pot won.
+type EquityResult struct {
+	Win    float64
+	Tie    float64
+	Equity float64
+}
+
+// Equity estimates each player's Texas Hold'em equity by dealing random
+// completions of the board and of players' hole cards.  Players may have
+// zero, one, or two known hole cards and the board may have zero to five
+// cards.  Dead cards are excluded from dealing.  Passing a rand.Rand with
+// a fixed seed produces reproducible results.  Equity panics if there
+// aren't enough cards remaining to deal.
+func Equity(players [][]*Card, board []*Card, dead []*Card, iterations int, r *rand.Rand) []EquityResult {
+	results, _ := EquityCtx(context.Background(), players, board, dead, iterations, r)
+	return results
+}
+
+// EquityCtx is the same as Equity except that it stops early if the
+// context is done and returns the context's error.  The results of a
+// stopped calculation only reflect the iterations completed before it
+// stopped.
+func EquityCtx(ctx context.Context, players [][]*Card, board []*Card, dead []*Card, iterations int, r *rand.Rand) ([]EquityResult, error) {
+	remaining := RemainingCards(knownCards(players, board, dead))
+	results := make([]EquityResult, len(players))
+	holeCards := make([][]*Card, len(players))
+	for i := range holeCards {
+		holeCards[i] = make([]*Card, 0, 2)
+	}
+	fullBoard := make([]*Card, 0, 5)
+
+	for i := 0; i < iterations; i++ {
+		if i%equityCtxInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return averageResults(results, i), err
+			}
+		}
+		// partial Fisher-Yates shuffle of the remaining cards
+		dealt := 0
+		deal := func() *Card {
+			j := dealt + r.Intn(len(remaining)-dealt)
+			remaining[dealt], remaining[j] = remaining[j], remaining[dealt]
+			dealt++
+			return remaining[dealt-1]
+		}
+		for p, cards := range players {
+			holeCards[p] = append(holeCards[p][:0], cards...)
+			for len(holeCards[p]) < 2 {
+				holeCards[p] = append(holeCards[p], deal())
+			}
+		}
+		fullBoard = append(fullBoard[:0], board...)
+		for len(fullBoard) < 5 {
+			fullBoard = append(fullBoard, deal())
+		}
+		showdown(holeCards, fullBoard, results)
+	}
+	return averageResults(results, iterations), nil
+}
+
+// EquityParallel is the same as Equity except that the iterations are
+// split between the given number of workers running concurrently.  Each
+// worker uses its own rand.Rand seeded from seed so results are
+// reproducible for the same seed and number of workers.
+func EquityParallel(players [][]*Card, board []*Card, dead []*Card, iterations, workers int, seed int64) []EquityResult {
+	if workers < 1 {
+		workers = 1
+	}
+	workerResults := make([][]EquityResult, workers)
+	workerIterations := make([]int, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		n := iterations / workers
+		if w < iterations%workers {
+			n++
+		}
+		workerIterations[w] = n
+		wg.Add(1)
+		go func(w, n int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed + int64(w)))
+			workerResults[w] = Equity(players, board, dead, n, r)
+		}(w, n)
+	}
+	wg.Wait()
+
+	results := make([]EquityResult, len(players))
+	for w, wResults := range workerResults {
+		n := float64(workerIterations[w])
+		for i, result := range wResults {
+			results[i].Win += result.Win * n
+			results[i].Tie += result.Tie * n
+			results[i].Equity += result.Equity * n
+		}
+	}
+	return averageResults(results, iterations)
+}
+
+// Ahead estimates the probability that hero's current best hand beats
+// the given number of opponents holding random hole cards on the board as
+// it is, without dealing the rest of the board.  Ties count as the
+// fraction of the pot hero would win.  Ahead panics if there aren't
+// enough cards remaining to deal.
+func Ahead(hero []*Card, board []*Card, opponents int, iterations int, r *rand.Rand) float64 {
+	remaining := RemainingCards(knownCards([][]*Card{hero}, board, nil))
+	heroHand := New(append(append([]*Card{}, hero...), board...))
+	hands := make([]*Hand, opponents+1)
+	ahead := 0.0
+	for i := 0; i < iterations; i++ {
+		// partial Fisher-Yates shuffle of the remaining cards
+		for dealt := 0; dealt < 2*opponents; dealt++ {
+			j := dealt + r.Intn(len(remaining)-dealt)
+			remaining[dealt], remaining[j] = remaining[j], remaining[dealt]
+		}
+		hands[0] = heroHand
+		for p := 1; p <= opponents; p++ {
+			cards := make([]*Card, 0, 2+len(board))
+			cards = append(cards, remaining[2*p-2:2*p]...)
+			hands[p] = New(append(cards, board...))
+		}
+		winners := Winners(hands)
+		for _, w := range winners {
+			if w == heroHand {
+				ahead += 1 / float64(len(winners))
+			}
+		}
+	}
+	if iterations == 0 {
+		return 0
+	}
+	return ahead / float64(iterations)
+}
+
+// An Outcome counts how often an opponent's hand beats, ties, or loses
+// to hero's hand.
+type Outcome struct {
+	Beat int
+	Tie  int
+	Lose int
+}
+
+// OpponentOutcome enumerates every pair of hole cards an opponent could
+// hold on the board as it is and counts, by the ranking of the
+// opponent's hand, how many beat, tie, or lose to hero's hand.
+func OpponentOutcome(hero []*Card, board []*Card) map[Ranking]Outcome {
+	remaining := RemainingCards(knownCards([][]*Card{hero}, board, nil))
+	heroHand := New(append(append([]*Card{}, hero...), board...))
+	outcomes := map[Ranking]Outcome{}
+	for _, combo := range util.Combinations(len(remaining), 2) {
+		cards := make([]*Card, 0, 2+len(board))
+		cards = append(cards, remaining[combo[0]], remaining[combo[1]])
+		h := New(append(cards, board...))
+		o := outcomes[h.Ranking()]
+		switch cmp := h.CompareTo(heroHand); {
+		case cmp > 0:
+			o.Beat++
+		case cmp == 0:
+			o.Tie++
+		default:
+			o.Lose++
+		}
+		outcomes[h.Ranking()] = o
+	}
+	return outcomes
+}
+
+// equityCtxInterval is the number of iterations between checks of
+// the context.
+const equityCtxInterval = 100
+
+// EquityExact calculates each player's Texas Hold'em equity by
+// enumerating every possible completion of the board.  Every player's
+// two hole cards must be known.  The completions are enumerated one at a
+// time, but with an empty board there are over a million of them so
+// EquityMonteCarlo is much faster before the flop.
+func EquityExact(players [][]*Card, board []*Card) []EquityResult {
+	remaining := RemainingCards(knownCards(players, board, nil))
+	results := make([]EquityResult, len(players))
+	if len(board) >= 5 {
+		showdown(players, board, results)
+		return averageResults(results, 1)
+	}
+
+	outcomes := 0
+	fullBoard := make([]*Card, 0, 5)
+	forEachCombination(len(remaining), 5-len(board), func(combo []int) {
+		fullBoard = append(fullBoard[:0], board...)
+		for _, i := range combo {
+			fullBoard = append(fullBoard, remaining[i])
+		}
+		showdown(players, fullBoard, results)
+		outcomes++
+	})
+	return averageResults(results, outcomes)
+}
+
+// forEachCombination calls fn with each combination of k indexes out of
+// n in the same order as util.Combinations w/o building them all first.
+// The slice passed to fn is reused between calls so fn must not retain
+// it.
+func forEachCombination(n, k int, fn func([]int)) {
+	if n <= 0 || k <= 0 || k > n {
+		return
+	}
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	for {
+		fn(indices)
+		i := k - 1
+		for ; i >= 0 && indices[i] == i+n-k; i-- {
+		}
+		if i < 0 {
+			return
+		}
+		indices[i]++
+		for j := i + 1; j < k; j++ {
+			indices[j] = indices[j-1] + 1
+		}
+	}
+}
+
+// showdown adds the outcome of the players' hole cards and the board
+// to results.
+func showdown(holeCards [][]*Card, board []*Card, results []EquityResult) {
+	hands := make([]*Hand, len(holeCards))
+	for i, hole := range holeCards {
+		cards := make([]*Card, 0, len(hole)+len(board))
+		cards = append(cards, hole...)
+		cards = append(cards, board...)
+		hands[i] = New(cards)
+	}
+
+	winners := WinnerIndices(hands)
+	share := 1 / float64(len(winners))
+	for _, i := range winners {
+		if len(winners) == 1 {
+			results[i].Win++
+		} else {
+			results[i].Tie++
+		}
+		results[i].Equity += share
+	}
+}
+
+// averageResults divides the accumulated results by the number of
+// outcomes.
+func averageResults(results []EquityResult, outcomes int) []EquityResult {
+	if outcomes == 0 {
+		return results
+	}
+	n := float64(outcomes)
+	for i := range results {
+		results[i].Win /= n
+		results[i].Tie /= n
+		results[i].Equity /= n
+	}
+	return results
+}
+
+func knownCards(players [][]*Card, board []*Card, dead []*Card) []*Card {
+	known := []*Card{}
+	for _, cards := range players {
+		known = append(known, cards...)
+	}
+	known = append(known, board...)
+	return append(known, dead...)
+}
@@ -0,0 +1,224 @@
+package hand_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+func TestEquity(t *testing.T) {
+	players := [][]*Card{
+		jokertest.Cards("As", "Ah"),
+		jokertest.Cards("Ks", "Kh"),
+	}
+	r := rand.New(rand.NewSource(7))
+	results := Equity(players, nil, nil, 1000, r)
+	if len(results) != 2 {
+		t.Fatalf("len(Equity()) = %d; want %d", len(results), 2)
+	}
+	if math.Abs(results[0].Equity-0.82) > 0.05 {
+		t.Fatalf("AA vs KK equity = %v; want about %v", results[0].Equity, 0.82)
+	}
+	if sum := results[0].Equity + results[1].Equity; math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("equities sum to %v; want 1", sum)
+	}
+
+	// the same seed reproduces the same results
+	again := Equity(players, nil, nil, 1000, rand.New(rand.NewSource(7)))
+	if again[0] != results[0] || again[1] != results[1] {
+		t.Fatalf("Equity() = %v; want %v", again, results)
+	}
+}
+
+func TestAhead(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	board := jokertest.Cards("Ac", "As", "7d", "7h", "2c")
+	if p := Ahead(jokertest.Cards("Ah", "Ad"), board, 3, 500, r); p != 1 {
+		t.Fatalf("Ahead() with quads = %v; want 1", p)
+	}
+
+	// every hand plays the royal flush on the board
+	board = jokertest.Cards("As", "Ks", "Qs", "Js", "Ts")
+	if p := Ahead(jokertest.Cards("2c", "3d"), board, 2, 500, r); math.Abs(p-1.0/3) > 1e-9 {
+		t.Fatalf("Ahead() with the board playing = %v; want %v", p, 1.0/3)
+	}
+
+	board = jokertest.Cards("Kd", "8c", "3s")
+	if p := Ahead(jokertest.Cards("Ks", "Kh"), board, 1, 1000, r); p < 0.95 {
+		t.Fatalf("Ahead() with top set = %v; want at least 0.95", p)
+	}
+	if p := Ahead(jokertest.Cards("4c", "2d"), board, 1, 1000, r); p > 0.2 {
+		t.Fatalf("Ahead() with four high = %v; want at most 0.2", p)
+	}
+}
+
+func TestOpponentOutcome(t *testing.T) {
+	board := jokertest.Cards("As", "Ks", "Qs", "7d", "2c")
+	outcomes := OpponentOutcome(jokertest.Cards("Ah", "Ad"), board)
+	total := 0
+	for _, o := range outcomes {
+		total += o.Beat + o.Tie + o.Lose
+	}
+	if total != 990 {
+		t.Fatalf("OpponentOutcome() counted %d combos; want %d", total, 990)
+	}
+	// only Js Ts makes a royal flush and beats top set
+	if o := outcomes[RoyalFlush]; o != (Outcome{Beat: 1}) {
+		t.Fatalf("OpponentOutcome()[%v] = %+v; want %+v", RoyalFlush, o, Outcome{Beat: 1})
+	}
+	if o := outcomes[ThreeOfAKind]; o.Beat != 0 || o.Lose == 0 {
+		t.Fatalf("OpponentOutcome()[%v] = %+v; want only losses", ThreeOfAKind, o)
+	}
+	if o := outcomes[Pair]; o.Beat != 0 || o.Tie != 0 {
+		t.Fatalf("OpponentOutcome()[%v] = %+v; want only losses", Pair, o)
+	}
+	if o := outcomes[Flush]; o.Beat == 0 || o.Lose != 0 {
+		t.Fatalf("OpponentOutcome()[%v] = %+v; want only wins", Flush, o)
+	}
+}
+
+// stopAfterCtx is a context that is done after Err has been called n
+// times.
+type stopAfterCtx struct {
+	context.Context
+	n int
+}
+
+func (c *stopAfterCtx) Err() error {
+	c.n--
+	if c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestEquityCtx(t *testing.T) {
+	players := [][]*Card{
+		jokertest.Cards("As", "Ah"),
+		jokertest.Cards("Ks", "Kh"),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := EquityCtx(ctx, players, nil, nil, 1000, rand.New(rand.NewSource(7)))
+	if err != context.Canceled || results[0] != (EquityResult{}) {
+		t.Fatalf("EquityCtx() = %v, %v; want no results and %v", results, err, context.Canceled)
+	}
+
+	ctx = &stopAfterCtx{Context: context.Background(), n: 3}
+	results, err = EquityCtx(ctx, players, nil, nil, 100000, rand.New(rand.NewSource(7)))
+	if err != context.Canceled {
+		t.Fatalf("EquityCtx() error = %v; want %v", err, context.Canceled)
+	}
+	if sum := results[0].Equity + results[1].Equity; math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("partial equities sum to %v; want 1", sum)
+	}
+
+	results, err = EquityCtx(context.Background(), players, nil, nil, 1000, rand.New(rand.NewSource(7)))
+	expected := Equity(players, nil, nil, 1000, rand.New(rand.NewSource(7)))
+	if err != nil || results[0] != expected[0] {
+		t.Fatalf("EquityCtx() = %v, %v; want %v", results, err, expected)
+	}
+}
+
+func TestEquityParallel(t *testing.T) {
+	players := [][]*Card{
+		jokertest.Cards("As", "Ah"),
+		jokertest.Cards("Ks", "Kh"),
+	}
+	results := EquityParallel(players, nil, nil, 1001, 4, 7)
+	if math.Abs(results[0].Equity-0.82) > 0.05 {
+		t.Fatalf("AA vs KK equity = %v; want about %v", results[0].Equity, 0.82)
+	}
+	if sum := results[0].Equity + results[1].Equity; math.Abs(sum-1) > 1e-9 {
+		t.Fatalf("equities sum to %v; want 1", sum)
+	}
+	again := EquityParallel(players, nil, nil, 1001, 4, 7)
+	if again[0] != results[0] || again[1] != results[1] {
+		t.Fatalf("EquityParallel() = %v; want %v", again, results)
+	}
+
+	single := EquityParallel(players, nil, nil, 500, 1, 7)
+	expected := Equity(players, nil, nil, 500, rand.New(rand.NewSource(7)))
+	if single[0] != expected[0] {
+		t.Fatalf("EquityParallel() with one worker = %v; want %v", single, expected)
+	}
+}
+
+func BenchmarkEquity(b *testing.B) {
+	players := [][]*Card{jokertest.Cards("As", "Ah"), jokertest.Cards("Ks", "Kh")}
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < b.N; i++ {
+		Equity(players, nil, nil, 10000, r)
+	}
+}
+
+func BenchmarkEquityParallel(b *testing.B) {
+	players := [][]*Card{jokertest.Cards("As", "Ah"), jokertest.Cards("Ks", "Kh")}
+	for i := 0; i < b.N; i++ {
+		EquityParallel(players, nil, nil, 10000, runtime.NumCPU(), 7)
+	}
+}
+
+func TestEquityCompleteBoard(t *testing.T) {
+	players := [][]*Card{
+		jokertest.Cards("As", "Kd"),
+		jokertest.Cards("Ac", "Kh"),
+		jokertest.Cards("2c", "3h"),
+	}
+	board := jokertest.Cards("Ah", "Kc", "7d", "8s", "Jh")
+	results := Equity(players, board, nil, 10, rand.New(rand.NewSource(1)))
+	expected := []EquityResult{
+		{Win: 0, Tie: 1, Equity: 0.5},
+		{Win: 0, Tie: 1, Equity: 0.5},
+		{Win: 0, Tie: 0, Equity: 0},
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Fatalf("Equity() = %v; want %v", results, expected)
+		}
+	}
+}
+
+func TestEquityUnknownCards(t *testing.T) {
+	players := [][]*Card{
+		jokertest.Cards("As", "Ad"),
+		{},
+	}
+	dead := jokertest.Cards("Ah", "Ac")
+	r := rand.New(rand.NewSource(3))
+	results := Equity(players, nil, dead, 1000, r)
+	if results[0].Equity < 0.75 || results[0].Equity > 0.9 {
+		t.Fatalf("AA vs random equity = %v; want about %v", results[0].Equity, 0.85)
+	}
+}
+
+func TestEquityExact(t *testing.T) {
+	players := [][]*Card{
+		jokertest.Cards("As", "Ad"),
+		jokertest.Cards("Ks", "Kd"),
+	}
+
+	// only the king of clubs on the river wins for kings
+	board := jokertest.Cards("Ah", "Kh", "2c", "3d")
+	results := EquityExact(players, board)
+	expected := []EquityResult{
+		{Win: 43.0 / 44.0, Tie: 0, Equity: 43.0 / 44.0},
+		{Win: 1.0 / 44.0, Tie: 0, Equity: 1.0 / 44.0},
+	}
+	for i := range expected {
+		if math.Abs(results[i].Equity-expected[i].Equity) > 1e-9 ||
+			math.Abs(results[i].Win-expected[i].Win) > 1e-9 || results[i].Tie != 0 {
+			t.Fatalf("EquityExact() = %v; want %v", results, expected)
+		}
+	}
+
+	results = EquityExact(players, append(board, jokertest.Cards("Kc")...))
+	if results[1].Win != 1 || results[0].Equity != 0 {
+		t.Fatalf("EquityExact() = %v; want kings to win", results)
+	}
+}
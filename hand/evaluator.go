@@ -0,0 +1,58 @@
+package hand
+
+import "fmt"
+
+// An Evaluator forms hands the same as New but reuses its buffers
+// between calls to reduce allocations in tight loops.  The hand returned
+// by Eval is overwritten by the next call so use Clone to keep it.  An
+// Evaluator is not safe for concurrent use so each goroutine should use
+// its own.  The zero value is ready to use.
+type Evaluator struct {
+	combo  []*Card
+	formed [2][5]*Card
+	hands  [2]Hand
+}
+
+// Eval returns the hand formed from the cards and configuration options
+// the same as New.  Hands formed with wild cards are formed by New and
+// aren't reused.  Eval panics if given malformed cards or cards that
+// can't be ranked.
+func (e *Evaluator) Eval(cards []*Card, options ...func(*Config)) *Hand {
+	c := newConfig(options)
+	if c.isWild != nil {
+		return New(cards, options...)
+	}
+	if err := validate(cards, c); err != nil {
+		panic(err)
+	}
+
+	// the best combination is found w/o forming a hand for each one
+	if len(cards) > 5 && c.standard() {
+		var ok bool
+		e.combo, ok = appendBestFastCombo(e.combo[:0], cards)
+		if !ok {
+			panic(fmt.Errorf("hand: no ranking found for cards %v", cards))
+		}
+		return e.rank(0, e.combo, *c)
+	}
+
+	// each combination is formed in whichever hand isn't the best so far
+	best := -1
+	forEachCombo(cards, func(combo []*Card) {
+		i := (best + 1) % len(e.hands)
+		h := e.rank(i, combo, *c)
+		if best < 0 || isBetter(h, &e.hands[best], c.sorting) {
+			best = i
+		}
+	})
+	return &e.hands[best]
+}
+
+// rank forms the combination into the i-th hand and its cards.
+func (e *Evaluator) rank(i int, combo []*Card, c Config) *Hand {
+	h := &e.hands[i]
+	if err := h.rank(formCardsInto(e.formed[i][:0], combo, c), c); err != nil {
+		panic(err)
+	}
+	return h
+}
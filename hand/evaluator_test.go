@@ -0,0 +1,57 @@
+package hand_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+func TestEvaluator(t *testing.T) {
+	e := &Evaluator{}
+	r := rand.New(rand.NewSource(17))
+	options := [][]func(*Config){nil, {AceToFiveLow}, {NoWheel}, {FlushSize(4)}, {WildRanks(Two)}}
+	for i := 0; i < 500; i++ {
+		deck := NewDeck()
+		deck.Shuffle(r.Shuffle)
+		cards := deck.PopMulti(3 + i%6)
+		opts := options[i%len(options)]
+		h, expected := e.Eval(cards, opts...), New(cards, opts...)
+		if !h.Ties(expected) || h.Description() != expected.Description() {
+			t.Fatalf("Eval(%v) = %v; want %v", cards, h, expected)
+		}
+	}
+}
+
+func TestEvaluatorReuse(t *testing.T) {
+	e := &Evaluator{}
+	royal := e.Eval(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts", "2c", "3d"))
+	kept := royal.Clone()
+	if h := e.Eval(jokertest.Cards("7s", "7h", "Kd", "9c", "4s", "3h", "2d")); h != royal || h.Ranking() != Pair {
+		t.Fatalf("Eval() = %v; want the hand reused as a pair", h)
+	}
+	if kept.Ranking() != RoyalFlush || kept.Cards()[0].Rank() != Ace {
+		t.Fatalf("Clone() = %v; want the royal flush kept", kept)
+	}
+}
+
+func TestEvaluatorUnrankableCards(t *testing.T) {
+	cards := jokertest.Cards("As", "As", "As", "As", "As", "Ks")
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Eval(%v) should panic", cards)
+		}
+	}()
+	(&Evaluator{}).Eval(cards)
+}
+
+func BenchmarkEvaluator(b *testing.B) {
+	cards := jokertest.Cards("As", "Kd", "Qh", "7c", "7s", "2h", "3d")
+	e := &Evaluator{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Eval(cards)
+	}
+}
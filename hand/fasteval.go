@@ -0,0 +1,228 @@
+package hand
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/notnil/joker/util"
+)
+
+// FastEval returns an integer value for the five given cards where a
+// higher value is a better high hand.  Values are consistent with
+// CompareTo so two hands tie if and only if their values are equal.
+// FastEval uses precomputed lookup tables and is much faster than New,
+// but it doesn't describe the hand.  FastEval panics if not given
+// exactly five valid cards.
+func FastEval(cards []*Card) int32 {
+	if len(cards) != 5 {
+		panic(fmt.Errorf("hand: FastEval requires five cards not %d", len(cards)))
+	}
+	fastEvalOnce.Do(initFastEval)
+	return fastEval(cards[0], cards[1], cards[2], cards[3], cards[4])
+}
+
+// FastEval7 returns the FastEval value of the best five card hand out of
+// the seven given cards.  FastEval7 doesn't allocate and panics if not
+// given exactly seven valid cards.
+func FastEval7(cards []*Card) int32 {
+	if len(cards) != 7 {
+		panic(fmt.Errorf("hand: FastEval7 requires seven cards not %d", len(cards)))
+	}
+	fastEvalOnce.Do(initFastEval)
+	best := int32(0)
+	for _, c := range sevenCardCombos {
+		v := fastEval(cards[c[0]], cards[c[1]], cards[c[2]], cards[c[3]], cards[c[4]])
+		if v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+// Strength returns the hand's position among the 7462 distinct five card
+// high hand values scaled between 0 and 1, so a royal flush is 1 and a
+// seven high is close to 0.  Strength is based on the hand's cards
+// regardless of the options it was formed with.  Hands formed from fewer
+// than five cards have a strength of 0 and five of a kind has a
+// strength of 1.
+func (h *Hand) Strength() float64 {
+	if h.ranking == FiveOfAKind {
+		return 1
+	}
+	if hasBlankCards(h.cards) {
+		return 0
+	}
+	fastEvalOnce.Do(initFastEval)
+	v := fastEval(h.cards[0], h.cards[1], h.cards[2], h.cards[3], h.cards[4])
+	return float64(v) / float64(len(valueRankings)-1)
+}
+
+// EnumerateDistinctHands returns a representative hand of each of the 7462
+// distinct five card high hand values from worst to best.  The hands are
+// computed once and shared by every call.
+func EnumerateDistinctHands() []*Hand {
+	fastEvalOnce.Do(initFastEval)
+	return append([]*Hand{}, distinctHands...)
+}
+
+var (
+	fastEvalOnce sync.Once
+
+	// sevenCardCombos are the indexes of the 21 five card combinations
+	// of seven cards
+	sevenCardCombos [21][5]int
+
+	// fastRanks maps ranks to their index and prime
+	fastRanks = map[Rank]struct{ index, prime int32 }{}
+
+	// flushValues is indexed by the rank bits of five suited cards
+	flushValues [1 << 13]int32
+
+	// uniqueValues is indexed by the rank bits of five unsuited cards
+	// with distinct ranks
+	uniqueValues [1 << 13]int32
+
+	// valueRankings is indexed by value
+	valueRankings []Ranking
+
+	// distinctHands is a hand of each value in ascending order
+	distinctHands []*Hand
+
+	// productValues is keyed by the product of the rank primes of
+	// hands with paired ranks
+	productValues = map[int32]int32{}
+)
+
+func fastEval(c1, c2, c3, c4, c5 *Card) int32 {
+	v, ok := fastEvalChecked(c1, c2, c3, c4, c5)
+	if !ok {
+		panic(fmt.Errorf("hand: FastEval given invalid cards %v", []*Card{c1, c2, c3, c4, c5}))
+	}
+	return v
+}
+
+// fastEvalChecked is the same as fastEval except that it returns false
+// instead of panicking if the cards don't form a valid hand.
+func fastEvalChecked(c1, c2, c3, c4, c5 *Card) (int32, bool) {
+	r1, r2, r3 := fastRanks[c1.rank], fastRanks[c2.rank], fastRanks[c3.rank]
+	r4, r5 := fastRanks[c4.rank], fastRanks[c5.rank]
+	bits := int32(1)<<uint(r1.index) | int32(1)<<uint(r2.index) |
+		int32(1)<<uint(r3.index) | int32(1)<<uint(r4.index) |
+		int32(1)<<uint(r5.index)
+	suit := c1.suit
+	if c2.suit == suit && c3.suit == suit && c4.suit == suit && c5.suit == suit {
+		v := flushValues[bits]
+		return v, v != 0
+	}
+	if v := uniqueValues[bits]; v != 0 {
+		return v, true
+	}
+	v, ok := productValues[r1.prime*r2.prime*r3.prime*r4.prime*r5.prime]
+	return v, ok
+}
+
+// initFastEval fills the lookup tables by ranking a representative hand
+// of each of the 7462 distinct five card hand values with CompareTo.
+func initFastEval() {
+	for i, combo := range util.Combinations(7, 5) {
+		copy(sevenCardCombos[i][:], combo)
+	}
+
+	primes := []int32{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41}
+	for i, r := range allRanks() {
+		fastRanks[r] = struct{ index, prime int32 }{int32(i), primes[i]}
+	}
+
+	hands := []*Hand{}
+	var form func(cards []*Card, minRank int)
+	form = func(cards []*Card, minRank int) {
+		if len(cards) == 5 {
+			hands = append(hands, New(cards))
+			if distinctRanks(cards) {
+				hands = append(hands, New(suited(cards, Spades)))
+			}
+			return
+		}
+		for i := minRank; i < len(allRanks()); i++ {
+			rank := allRanks()[i]
+			if len(cardsForRank(cards, rank)) == 4 {
+				continue
+			}
+			// alternate suits so that ranks are unique and flushes impossible
+			suit := allSuits()[len(cards)%4]
+			form(append(cards[:len(cards):len(cards)], &Card{rank: rank, suit: suit}), i)
+		}
+	}
+	form([]*Card{}, 0)
+
+	sort.Sort(ByHighHand(hands))
+	value := int32(0)
+	valueRankings = []Ranking{0}
+	for i, h := range hands {
+		if i == 0 || h.CompareTo(hands[i-1]) != 0 {
+			value++
+			valueRankings = append(valueRankings, h.Ranking())
+			distinctHands = append(distinctHands, h)
+		}
+		cards := h.cards
+		bits, product := int32(0), int32(1)
+		for _, c := range cards {
+			bits |= 1 << uint(fastRanks[c.rank].index)
+			product *= fastRanks[c.rank].prime
+		}
+		switch {
+		case hasFlush(cards):
+			flushValues[bits] = value
+		case distinctRanks(cards):
+			uniqueValues[bits] = value
+		default:
+			productValues[product] = value
+		}
+	}
+}
+
+func distinctRanks(cards []*Card) bool {
+	for _, c := range cards {
+		if len(cardsForRank(cards, c.Rank())) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func suited(cards []*Card, s Suit) []*Card {
+	suited := []*Card{}
+	for _, c := range cards {
+		suited = append(suited, &Card{rank: c.rank, suit: s})
+	}
+	return suited
+}
+
+// bestFastCombo returns the five card combination of cards with the
+// highest FastEval value.  Combinations that aren't valid hands, such as
+// those with repeated cards, are skipped and bestFastCombo returns false
+// if no combination is valid.
+func bestFastCombo(cards []*Card) ([]*Card, bool) {
+	return appendBestFastCombo(make([]*Card, 0, 5), cards)
+}
+
+// appendBestFastCombo appends the combination from bestFastCombo to dst.
+func appendBestFastCombo(dst, cards []*Card) ([]*Card, bool) {
+	fastEvalOnce.Do(initFastEval)
+	var best []int
+	bestValue := int32(0)
+	for _, c := range combinations(len(cards), 5) {
+		v, ok := fastEvalChecked(cards[c[0]], cards[c[1]], cards[c[2]], cards[c[3]], cards[c[4]])
+		if ok && v > bestValue {
+			best, bestValue = c, v
+		}
+	}
+	if best == nil {
+		return dst, false
+	}
+	for _, j := range best {
+		dst = append(dst, cards[j])
+	}
+	return dst, true
+}
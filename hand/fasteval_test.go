@@ -0,0 +1,141 @@
+package hand_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+func TestFastEval(t *testing.T) {
+	for _, test := range tests {
+		if len(test.cards) != 5 {
+			continue
+		}
+		if FastEval(test.cards) <= 0 {
+			t.Fatalf("FastEval(%v) should be positive", test.cards)
+		}
+	}
+
+	royal := FastEval(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"))
+	wheel := FastEval(jokertest.Cards("5d", "4s", "3s", "2s", "As"))
+	worst := FastEval(jokertest.Cards("7d", "5s", "4s", "3s", "2h"))
+	if royal != 7462 || worst != 1 || wheel <= worst {
+		t.Fatalf("FastEval() royal = %d, wheel = %d, worst = %d", royal, wheel, worst)
+	}
+
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 2000; i++ {
+		deck := NewDeck()
+		deck.Shuffle(r.Shuffle)
+		c1, c2 := deck.PopMulti(5), deck.PopMulti(5)
+		v1, v2 := FastEval(c1), FastEval(c2)
+		cmp := New(c1).CompareTo(New(c2))
+		if (v1 > v2) != (cmp > 0) || (v1 == v2) != (cmp == 0) {
+			t.Fatalf("FastEval(%v) = %d, FastEval(%v) = %d; CompareTo = %d", c1, v1, c2, v2, cmp)
+		}
+	}
+}
+
+func TestFastEval7(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for i := 0; i < 500; i++ {
+		deck := NewDeck()
+		deck.Shuffle(r.Shuffle)
+		cards := deck.PopMulti(7)
+		best := New(cards)
+		if v := FastEval7(cards); v != FastEval(best.Cards()) {
+			t.Fatalf("FastEval7(%v) = %d; want %d for %v", cards, v, FastEval(best.Cards()), best)
+		}
+	}
+}
+
+func TestFastEvalPanics(t *testing.T) {
+	cards := jokertest.Cards("As", "As", "As", "As", "As", "Ks", "Qs")
+	for name, fn := range map[string]func(){
+		"FastEval four cards":   func() { FastEval(cards[:4]) },
+		"FastEval invalid":      func() { FastEval(cards[:5]) },
+		"FastEval7 six cards":   func() { FastEval7(cards[:6]) },
+		"FastEval7 eight cards": func() { FastEval7(append(cards, TwoClubs)) },
+	} {
+		func() {
+			defer func() {
+				if _, ok := recover().(error); !ok {
+					t.Fatalf("%s should panic with an error", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestEnumerateDistinctHands(t *testing.T) {
+	hands := EnumerateDistinctHands()
+	if len(hands) != 7462 {
+		t.Fatalf("len(EnumerateDistinctHands()) = %d; want %d", len(hands), 7462)
+	}
+	for i := 1; i < len(hands); i++ {
+		if !hands[i].Beats(hands[i-1]) {
+			t.Fatalf("expected %v to beat %v", hands[i], hands[i-1])
+		}
+	}
+	if hands[0].ShortDescription() != "HC 75432" || hands[len(hands)-1].Ranking() != RoyalFlush {
+		t.Fatalf("EnumerateDistinctHands() is from %v to %v", hands[0], hands[len(hands)-1])
+	}
+	for i, h := range hands {
+		if int(FastEval(h.Cards())) != i+1 {
+			t.Fatalf("FastEval(%v) = %d; want %d", h, FastEval(h.Cards()), i+1)
+		}
+	}
+	counts := map[Ranking]int{}
+	for _, h := range hands {
+		counts[h.Ranking()]++
+	}
+	if counts[Straight] != 10 || counts[Pair] != 2860 || counts[Flush] != 1277 {
+		t.Fatalf("EnumerateDistinctHands() ranking counts = %v", counts)
+	}
+}
+
+func TestHandStrength(t *testing.T) {
+	royal := New(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"))
+	worst := New(jokertest.Cards("7d", "5s", "4s", "3s", "2h"))
+	pair := New(jokertest.Cards("As", "Ad", "7c", "4s", "2h"))
+	if royal.Strength() != 1 {
+		t.Fatalf("Strength() = %v for %v; want 1", royal.Strength(), royal)
+	}
+	if s := worst.Strength(); s <= 0 || s > 0.001 {
+		t.Fatalf("Strength() = %v for %v; want close to 0", s, worst)
+	}
+	if s := pair.Strength(); s <= worst.Strength() || s >= royal.Strength() {
+		t.Fatalf("Strength() = %v for %v; want between %v and %v", s, pair, worst, royal)
+	}
+	if s := New(jokertest.Cards("As", "Ad")).Strength(); s != 0 {
+		t.Fatalf("Strength() = %v for a partial hand; want 0", s)
+	}
+}
+
+func BenchmarkFastEval7(b *testing.B) {
+	cards := NewDealer().Deck().PopMulti(7)
+	FastEval7(cards)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FastEval7(cards)
+	}
+}
+
+func BenchmarkFastEval(b *testing.B) {
+	cards := NewDealer().Deck().PopMulti(5)
+	FastEval(cards)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FastEval(cards)
+	}
+}
+
+func BenchmarkNewFiveCards(b *testing.B) {
+	cards := NewDealer().Deck().PopMulti(5)
+	for i := 0; i < b.N; i++ {
+		New(cards)
+	}
+}
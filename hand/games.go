@@ -0,0 +1,209 @@
+package hand
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidBoard is returned when the number of board cards isn't
+// valid for the game being evaluated.
+var ErrInvalidBoard = errors.New("hand: invalid number of board cards")
+
+// ErrInvalidHoleCards is returned when the number of hole cards isn't
+// valid for the game being evaluated.
+var ErrInvalidHoleCards = errors.New("hand: invalid number of hole cards")
+
+// BestHoldemHand returns the best five card hand formed from the two
+// hole cards and the board.  BestHoldemHand panics if the board doesn't
+// have between three and five cards, use BestHoldemHandErr to receive an
+// error instead.
+func BestHoldemHand(hole [2]*Card, board []*Card, options ...func(*Config)) *Hand {
+	h, err := BestHoldemHandErr(hole, board, options...)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// BestHoldemHandErr is the same as BestHoldemHand except that it returns
+// ErrInvalidBoard if the board doesn't have between three and five cards.
+func BestHoldemHandErr(hole [2]*Card, board []*Card, options ...func(*Config)) (*Hand, error) {
+	if len(board) < 3 || len(board) > 5 {
+		return nil, ErrInvalidBoard
+	}
+	cards := append([]*Card{hole[0], hole[1]}, board...)
+	return NewErr(cards, options...)
+}
+
+// NutHand returns the best possible Hold'em hand on the board and the hole
+// cards that make it.  When several hole cards make equal hands the first
+// in the order of Cards is returned.  NutHand panics if the board doesn't
+// have between three and five cards.
+func NutHand(board []*Card) (*Hand, [2]*Card) {
+	var best *Hand
+	var nuts [2]*Card
+	remaining := RemainingCards(board)
+	for _, combo := range combinations(len(remaining), 2) {
+		hole := [2]*Card{remaining[combo[0]], remaining[combo[1]]}
+		h := BestHoldemHand(hole, board)
+		if best == nil || h.Beats(best) {
+			best, nuts = h, hole
+		}
+	}
+	return best, nuts
+}
+
+// BestFlush returns the highest flush that can be formed from the cards
+// and whether there is one.  Straight flushes and royal flushes are
+// included and beat any other flush.  Options that sort low or ignore
+// straights or flushes are overridden, but the ace is still low if the
+// options make it low, as AceToFiveLow does.  With FlushSize a flush of
+// fewer than five cards is completed with the highest other cards.
+func BestFlush(cards []*Card, options ...func(*Config)) (*Hand, bool) {
+	options = append(options[:len(options):len(options)], func(c *Config) {
+		c.sorting = SortingHigh
+		c.ignoreStraights = false
+		c.ignoreFlushes = false
+	})
+	c := newConfig(options)
+	size := c.flushSize
+	if size == 0 {
+		size = 5
+	}
+	sorted := append([]*Card{}, cards...)
+	if c.aceIsLow {
+		sort.Stable(sort.Reverse(byAceLow(sorted)))
+	} else {
+		sort.Stable(sort.Reverse(byAceHigh(sorted)))
+	}
+	var best *Hand
+	for _, s := range allSuits() {
+		suited, others := []*Card{}, []*Card{}
+		for _, card := range sorted {
+			if card.Suit() == s {
+				suited = append(suited, card)
+			} else {
+				others = append(others, card)
+			}
+		}
+		if len(suited) < size {
+			continue
+		}
+		for len(suited) < 5 && len(others) > 0 {
+			suited, others = append(suited, others[0]), others[1:]
+		}
+		h := New(suited, options...)
+		if best == nil || h.Beats(best) {
+			best = h
+		}
+	}
+	return best, best != nil
+}
+
+// BestOmahaHand returns the best five card hand formed from exactly two
+// of the four hole cards and exactly three of the five board cards.
+// BestOmahaHand panics if given malformed cards.
+func BestOmahaHand(hole [4]*Card, board [5]*Card, options ...func(*Config)) *Hand {
+	c := newConfig(options)
+	hands := []*Hand{}
+	for _, hCombo := range combinations(len(hole), 2) {
+		for _, bCombo := range combinations(len(board), 3) {
+			cards := []*Card{hole[hCombo[0]], hole[hCombo[1]],
+				board[bCombo[0]], board[bCombo[1]], board[bCombo[2]]}
+			hands = append(hands, New(cards, options...))
+		}
+	}
+	return Sort(c.sorting, DESC, hands...)[0]
+}
+
+// BestPineappleHand returns the best five card hand formed from the three
+// hole cards of a pineapple hand and the five board cards.  Any number of
+// the hole cards may be used.  BestPineappleHand panics if given
+// malformed cards.
+func BestPineappleHand(hole [3]*Card, board [5]*Card, options ...func(*Config)) *Hand {
+	return New(append(hole[:len(hole):len(hole)], board[:]...), options...)
+}
+
+// BestCrazyPineappleHand is the same as BestPineappleHand except that it
+// accepts the two hole cards left after crazy pineapple's discard as well
+// as all three, and a board of three to five cards.  It returns
+// ErrInvalidHoleCards or ErrInvalidBoard if there are the wrong number of
+// hole or board cards.
+func BestCrazyPineappleHand(hole []*Card, board []*Card, options ...func(*Config)) (*Hand, error) {
+	if len(hole) < 2 || len(hole) > 3 {
+		return nil, ErrInvalidHoleCards
+	}
+	if len(board) < 3 || len(board) > 5 {
+		return nil, ErrInvalidBoard
+	}
+	cards := append(append([]*Card{}, hole...), board...)
+	return NewErr(cards, options...)
+}
+
+// BestStudHand returns the best five card hand formed from the seven
+// cards of a seven card stud hand.
+func BestStudHand(cards [7]*Card, options ...func(*Config)) *Hand {
+	return New(cards[:], options...)
+}
+
+// StudStreetHand returns the best hand formed from the first street cards
+// dealt in seven card stud, from three on third street to seven on
+// seventh street.  Hands on third and fourth street have fewer than five
+// cards so they are partial and can only make pairs, two pair, three of
+// a kind, or four of a kind.  StudStreetHand returns an error if the
+// street is invalid or not enough cards have been dealt.
+func StudStreetHand(cards []*Card, street int, options ...func(*Config)) (*Hand, error) {
+	if street < 3 || street > 7 || len(cards) < street {
+		return nil, fmt.Errorf("hand: can't form stud hand for street %d from %d cards", street, len(cards))
+	}
+	return NewErr(cards[:street], options...)
+}
+
+// BestRazzHand returns the best ace to five low hand formed from the cards
+// of a razz hand.  Aces are low and straights and flushes don't count so
+// the best possible hand is 5-4-3-2-A.
+func BestRazzHand(cards []*Card) *Hand {
+	return New(cards, AceToFiveLow)
+}
+
+// ShowdownHiLo returns the indexes of the players that win the high and
+// low halves of a hi/lo split pot.  The high hands are formed with the
+// given options while the low hands are ace to five lows that must be
+// eight or better to qualify.  lowWinners is empty if no player
+// qualifies for low.
+func ShowdownHiLo(playerCards [][]*Card, options ...func(*Config)) (highWinners, lowWinners []int) {
+	highs := []*Hand{}
+	lows := []*Hand{}
+	lowPlayers := []int{}
+	for i, cards := range playerCards {
+		highs = append(highs, New(cards, options...))
+		if h, ok := QualifiesLow(cards); ok {
+			lows = append(lows, h)
+			lowPlayers = append(lowPlayers, i)
+		}
+	}
+
+	lowWinners = []int{}
+	for _, i := range WinnerIndices(lows) {
+		lowWinners = append(lowWinners, lowPlayers[i])
+	}
+	return WinnerIndices(highs), lowWinners
+}
+
+// QualifiesLow returns the best ace to five low hand and whether it
+// qualifies as eight or better.
+func QualifiesLow(cards []*Card) (*Hand, bool) {
+	return QualifiesLowMax(cards, Eight)
+}
+
+// QualifiesLowMax returns the best ace to five low hand and whether it
+// is made of five unpaired cards ranked max or lower.  For example,
+// QualifiesLowMax(cards, Seven) checks for a seven or better low.
+func QualifiesLowMax(cards []*Card, max Rank) (*Hand, bool) {
+	h := New(cards, AceToFiveLow)
+	if h.Ranking() != HighCard || hasBlankCards(h.cards) {
+		return h, false
+	}
+	return h, h.cards[0].Rank().aceLowIndexOf() <= max.aceLowIndexOf()
+}
@@ -0,0 +1,253 @@
+package hand_test
+
+import (
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+func TestBestHoldemHand(t *testing.T) {
+	hole := [2]*Card{AceSpades, AceHearts}
+	board := jokertest.Cards("Ad", "Kc", "Ks", "2h", "3d")
+	h := BestHoldemHand(hole, board)
+	if h.Ranking() != FullHouse {
+		t.Fatalf("expected %v got %v", FullHouse, h.Ranking())
+	}
+
+	h = BestHoldemHand(hole, board[:3])
+	if h.Ranking() != FullHouse {
+		t.Fatalf("expected %v got %v", FullHouse, h.Ranking())
+	}
+
+	for _, b := range [][]*Card{nil, board[:2], append(board, TwoSpades)} {
+		if _, err := BestHoldemHandErr(hole, b); err != ErrInvalidBoard {
+			t.Fatalf("BestHoldemHandErr(%v, %v) error = %v; want %v", hole, b, err, ErrInvalidBoard)
+		}
+	}
+}
+
+func TestNutHand(t *testing.T) {
+	tests := []struct {
+		board   []*Card
+		ranking Ranking
+		hole    [2]*Card
+	}{
+		{jokertest.Cards("Ks", "Qs", "Js", "4d", "2c"), RoyalFlush, [2]*Card{AceSpades, TenSpades}},
+		{jokertest.Cards("Ks", "Kd", "7c", "4d", "2c"), FourOfAKind, [2]*Card{KingHearts, KingClubs}},
+		{jokertest.Cards("9s", "7d", "2c"), ThreeOfAKind, [2]*Card{NineHearts, NineDiamonds}},
+	}
+	for _, test := range tests {
+		h, hole := NutHand(test.board)
+		if h.Ranking() != test.ranking {
+			t.Fatalf("NutHand(%v) = %v; want %v", test.board, h, test.ranking)
+		}
+		if !hole[0].Equal(test.hole[0]) || !hole[1].Equal(test.hole[1]) {
+			t.Fatalf("NutHand(%v) hole cards = %v; want %v", test.board, hole, test.hole)
+		}
+	}
+}
+
+func TestBestFlush(t *testing.T) {
+	cards := jokertest.Cards("Kh", "9h", "6h", "4h", "2h", "Ad", "Qd", "Jd", "Td", "3d")
+	h, ok := BestFlush(cards)
+	if !ok || h.Description() != "flush ace high" || h.Cards()[0] != AceDiamonds {
+		t.Fatalf("BestFlush(%v) = %v, %v; want flush ace high in diamonds", cards, h, ok)
+	}
+	if h := New(cards); h.Ranking() != Flush || h.Cards()[0] != AceDiamonds {
+		t.Fatalf("New(%v) = %v; want the same flush", cards, h)
+	}
+
+	// the ace is the lowest card when aces are low
+	h, ok = BestFlush(cards, AceToFiveLow)
+	if !ok || h.Ranking() != Flush || h.Description() != "flush king high" || h.Cards()[0] != KingHearts {
+		t.Fatalf("BestFlush(%v, AceToFiveLow) = %v, %v; want flush king high in hearts", cards, h, ok)
+	}
+
+	if h, ok := BestFlush(jokertest.Cards("9s", "8s", "7s", "6s", "5s", "2c")); !ok || h.Ranking() != StraightFlush {
+		t.Fatalf("BestFlush() = %v, %v; want %v", h, ok, StraightFlush)
+	}
+	// the straight flush beats a higher flush even when sorting low
+	cards = jokertest.Cards("9s", "8s", "7s", "6s", "5s", "Ah", "Kh", "Qh", "Jh", "9h")
+	for _, options := range [][]func(*Config){nil, {Low}, {DeuceToSevenLow}, {AceToFiveLow}} {
+		h, ok := BestFlush(cards, options...)
+		if !ok || h.Ranking() != StraightFlush || h.Cards()[0] != NineSpades {
+			t.Fatalf("BestFlush(%v) = %v, %v; want a nine high straight flush", cards, h, ok)
+		}
+	}
+	// a four card flush is completed with the highest other card
+	cards = jokertest.Cards("Ks", "Qs", "9s", "4s", "Ah", "Jd", "Jc")
+	if h := New(cards, FlushSize(4)); h.Ranking() != Flush {
+		t.Fatalf("New(%v, FlushSize(4)) = %v; want %v", cards, h, Flush)
+	}
+	h, ok = BestFlush(cards, FlushSize(4))
+	if !ok || h.Ranking() != Flush || h.Cards()[0] != KingSpades || h.Cards()[4] != AceHearts {
+		t.Fatalf("BestFlush(%v, FlushSize(4)) = %v, %v; want flush king high with an ace", cards, h, ok)
+	}
+	if h, ok := BestFlush(cards); ok || h != nil {
+		t.Fatalf("BestFlush(%v) = %v, %v; want no flush", cards, h, ok)
+	}
+
+	if h, ok := BestFlush(jokertest.Cards("9s", "8s", "7s", "6s", "5h")); ok || h != nil {
+		t.Fatalf("BestFlush() = %v, %v; want no flush", h, ok)
+	}
+}
+
+func TestBestOmahaHand(t *testing.T) {
+	// the board has four spades but only one spade is held
+	hole := [4]*Card{AceSpades, AceHearts, KingDiamonds, QueenClubs}
+	board := [5]*Card{TwoSpades, SevenSpades, NineSpades, JackSpades, ThreeHearts}
+	h := BestOmahaHand(hole, board)
+	if h.Ranking() != Pair {
+		t.Fatalf("expected %v got %v", Pair, h.Ranking())
+	}
+	if h := New(append(hole[:], board[:]...)); h.Ranking() != Flush {
+		t.Fatalf("expected %v got %v", Flush, h.Ranking())
+	}
+
+	// two spades held makes the flush
+	hole[1] = KingSpades
+	h = BestOmahaHand(hole, board)
+	if h.Ranking() != Flush {
+		t.Fatalf("expected %v got %v", Flush, h.Ranking())
+	}
+}
+
+func TestBestPineappleHand(t *testing.T) {
+	// all three hole cards play in the full house
+	hole := [3]*Card{KingSpades, KingHearts, TwoClubs}
+	board := [5]*Card{KingDiamonds, TwoSpades, SevenHearts, NineClubs, JackDiamonds}
+	if h := BestPineappleHand(hole, board); h.Ranking() != FullHouse {
+		t.Fatalf("BestPineappleHand() = %v; want %v", h, FullHouse)
+	}
+
+	// the deuce was discarded in crazy pineapple
+	h, err := BestCrazyPineappleHand(hole[:2], board[:3])
+	if err != nil || h.Ranking() != ThreeOfAKind {
+		t.Fatalf("BestCrazyPineappleHand() = %v, %v; want %v", h, err, ThreeOfAKind)
+	}
+	if _, err := BestCrazyPineappleHand(hole[:1], board[:]); err != ErrInvalidHoleCards {
+		t.Fatalf("BestCrazyPineappleHand() error = %v; want %v", err, ErrInvalidHoleCards)
+	}
+	if _, err := BestCrazyPineappleHand(hole[:], board[:2]); err != ErrInvalidBoard {
+		t.Fatalf("BestCrazyPineappleHand() error = %v; want %v", err, ErrInvalidBoard)
+	}
+}
+
+func TestBestStudHand(t *testing.T) {
+	cards := [7]*Card{KingSpades, KingHearts, TwoClubs, KingDiamonds, SevenHearts, TwoSpades, AceSpades}
+	if h := BestStudHand(cards); h.Ranking() != FullHouse {
+		t.Fatalf("BestStudHand(%v) = %v; want %v", cards, h, FullHouse)
+	}
+
+	streets := []struct {
+		ranking Ranking
+		partial bool
+	}{
+		{Pair, true},
+		{ThreeOfAKind, true},
+		{ThreeOfAKind, false},
+		{FullHouse, false},
+		{FullHouse, false},
+	}
+	for i, test := range streets {
+		street := i + 3
+		h, err := StudStreetHand(cards[:street], street)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Ranking() != test.ranking || h.IsPartial() != test.partial {
+			t.Fatalf("StudStreetHand(%d) = %v; want %v", street, h, test.ranking)
+		}
+	}
+
+	for _, street := range []int{2, 8} {
+		if _, err := StudStreetHand(cards[:], street); err == nil {
+			t.Fatalf("StudStreetHand(%d) should return an error", street)
+		}
+	}
+	if _, err := StudStreetHand(cards[:4], 5); err == nil {
+		t.Fatal("StudStreetHand() should return an error if the street hasn't been dealt")
+	}
+}
+
+func TestBestRazzHand(t *testing.T) {
+	tests := []struct {
+		cards       []*Card
+		description string
+	}{
+		{jokertest.Cards("As", "2s", "3s", "4s", "5s", "Kd", "Kh"), "five low"},
+		{jokertest.Cards("Ks", "Kh", "7c", "7d", "4s", "2h", "3c"), "king low"},
+		{jokertest.Cards("As", "Ah", "8c", "8d", "7s", "6h", "3c"), "eight low"},
+		{jokertest.Cards("9s", "9h", "9c", "Ad", "Qs", "Qh", "Qc"), "two pair queens and nines"},
+	}
+	for _, test := range tests {
+		if h := BestRazzHand(test.cards); h.Description() != test.description {
+			t.Fatalf("BestRazzHand(%v) = %v; want %v", test.cards, h, test.description)
+		}
+	}
+
+	nuts := BestRazzHand(jokertest.Cards("5s", "4s", "3s", "2s", "As"))
+	sixLow := BestRazzHand(jokertest.Cards("6s", "4s", "3s", "2s", "As", "Kh", "Kd"))
+	if Winners([]*Hand{sixLow, nuts})[0] != nuts {
+		t.Fatalf("expected %v to beat %v", nuts, sixLow)
+	}
+}
+
+func TestShowdownHiLo(t *testing.T) {
+	board := jokertest.Cards("Ah", "4d", "5c", "Kd", "Ks")
+	players := [][]*Card{
+		append(jokertest.Cards("Kh", "Ac"), board...),
+		append(jokertest.Cards("2h", "3c"), board...),
+		append(jokertest.Cards("2d", "3d"), board...),
+	}
+	high, low := ShowdownHiLo(players)
+	if len(high) != 1 || high[0] != 0 {
+		t.Fatalf("high winners = %v; want %v", high, []int{0})
+	}
+	if len(low) != 2 || low[0] != 1 || low[1] != 2 {
+		t.Fatalf("low winners = %v; want %v", low, []int{1, 2})
+	}
+
+	// no low is possible with only two low board cards
+	board = jokertest.Cards("Ah", "4d", "Tc", "Kd", "Ks")
+	players = [][]*Card{
+		append(jokertest.Cards("Kh", "Qc"), board...),
+		append(jokertest.Cards("2h", "9c"), board...),
+	}
+	high, low = ShowdownHiLo(players)
+	if len(high) != 1 || high[0] != 0 {
+		t.Fatalf("high winners = %v; want %v", high, []int{0})
+	}
+	if len(low) != 0 {
+		t.Fatalf("low winners = %v; want none", low)
+	}
+}
+
+func TestQualifiesLow(t *testing.T) {
+	tests := []struct {
+		cards []*Card
+		max   Rank
+		ok    bool
+	}{
+		{jokertest.Cards("8h", "7d", "6c", "5d", "4s"), Eight, true},
+		{jokertest.Cards("Ah", "2d", "3c", "4d", "5s"), Eight, true},
+		{jokertest.Cards("9h", "7d", "6c", "5d", "4s"), Eight, false},
+		{jokertest.Cards("8h", "8d", "6c", "5d", "4s"), Eight, false},
+		{jokertest.Cards("8h", "8d", "6c", "5d", "4s", "Ah", "Kc"), Eight, true},
+		{jokertest.Cards("8h", "7d", "6c", "5d", "4s"), Seven, false},
+		{jokertest.Cards("7h", "6d", "4c", "3d", "As"), Seven, true},
+		{jokertest.Cards("Ah", "2d", "3c"), Eight, false},
+	}
+	for _, test := range tests {
+		h, ok := QualifiesLowMax(test.cards, test.max)
+		if ok != test.ok {
+			t.Fatalf("QualifiesLowMax(%v, %v) = %v, %v; want %v", test.cards, test.max, h, ok, test.ok)
+		}
+		if test.max == Eight {
+			if _, ok := QualifiesLow(test.cards); ok != test.ok {
+				t.Fatalf("QualifiesLow(%v) = %v; want %v", test.cards, ok, test.ok)
+			}
+		}
+	}
+}
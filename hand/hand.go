@@ -2,9 +2,12 @@ package hand
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/bits"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/notnil/joker/util"
 )
@@ -56,8 +59,93 @@ const (
 	// of the same suit.
 	// Ex: A♥ K♥ Q♥ J♥ T♥
 	RoyalFlush
+
+	// FiveOfAKind represents a hand composed of five cards of the same rank.
+	// It is only possible with wild cards.
+	// Ex: A♠ A♣ A♦ A♥ 2♠ with deuces wild
+	FiveOfAKind
 )
 
+// Example returns the example hand from the ranking's documentation such
+// as A♠ A♣ K♣ J♥ 5♦ for Pair.  Example returns nil for an unknown ranking.
+func (r Ranking) Example() []*Card {
+	s, ok := rankingExamples[r]
+	if !ok {
+		return nil
+	}
+	cards, err := ParseCards(s)
+	if err != nil {
+		panic(err)
+	}
+	return cards
+}
+
+var rankingExamples = map[Ranking]string{
+	HighCard:      "A♠ K♠ J♣ 7♥ 5♦",
+	Pair:          "A♠ A♣ K♣ J♥ 5♦",
+	TwoPair:       "A♠ A♣ J♣ J♦ 5♦",
+	ThreeOfAKind:  "A♠ A♣ A♦ J♥ 5♦",
+	Straight:      "A♠ K♣ Q♦ J♥ T♦",
+	Flush:         "T♠ 7♠ 4♠ 3♠ 2♠",
+	FullHouse:     "4♠ 4♣ 4♦ 2♠ 2♥",
+	FourOfAKind:   "A♠ A♣ A♦ A♥ 5♥",
+	StraightFlush: "5♥ 4♥ 3♥ 2♥ A♥",
+	RoyalFlush:    "A♥ K♥ Q♥ J♥ T♥",
+	FiveOfAKind:   "A♠ A♣ A♦ A♥ 2♠",
+}
+
+// MarshalRankingNames controls whether rankings, including those in hand
+// json, are marshalled as names such as "royal flush" instead of integers.
+var MarshalRankingNames = false
+
+// MarshalJSON implements the json.Marshaler interface.  The ranking is
+// marshalled as an integer unless MarshalRankingNames is true.
+func (r Ranking) MarshalJSON() ([]byte, error) {
+	if !MarshalRankingNames {
+		return json.Marshal(int(r))
+	}
+	name, ok := rankingNames[r]
+	if !ok {
+		return nil, fmt.Errorf("hand: can't marshal unknown ranking %d", r)
+	}
+	return json.Marshal(name)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.  Either an
+// integer or a name such as "royal flush" is accepted.
+func (r *Ranking) UnmarshalJSON(b []byte) error {
+	var i int
+	if err := json.Unmarshal(b, &i); err == nil {
+		*r = Ranking(i)
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	for ranking, n := range rankingNames {
+		if n == name {
+			*r = ranking
+			return nil
+		}
+	}
+	return fmt.Errorf("hand: can't unmarshal unknown ranking %q", name)
+}
+
+var rankingNames = map[Ranking]string{
+	HighCard:      "high card",
+	Pair:          "pair",
+	TwoPair:       "two pair",
+	ThreeOfAKind:  "three of a kind",
+	Straight:      "straight",
+	Flush:         "flush",
+	FullHouse:     "full house",
+	FourOfAKind:   "four of a kind",
+	StraightFlush: "straight flush",
+	RoyalFlush:    "royal flush",
+	FiveOfAKind:   "five of a kind",
+}
+
 // Sorting is the sorting used to determine which hand is
 // selected.
 type Sorting int
@@ -83,10 +171,17 @@ const (
 
 // Config represents the configuration options for hand selection
 type Config struct {
-	sorting         Sorting
-	ignoreStraights bool
-	ignoreFlushes   bool
-	aceIsLow        bool
+	sorting           Sorting
+	ignoreStraights   bool
+	ignoreFlushes     bool
+	aceIsLow          bool
+	ignoreLowStraight bool
+	rejectDuplicates  bool
+	shortDeck         bool
+	aroundTheCorner   bool
+	flushSize         int
+	isWild            func(*Card) bool
+	bug               bool
 }
 
 // Low configures NewHand to select the lowest hand in which aces
@@ -104,33 +199,217 @@ func AceToFiveLow(c *Config) {
 	c.ignoreFlushes = true
 }
 
+// ParseHand parses the cards in the format accepted by ParseCards and
+// forms a hand from them using the given options.
+func ParseHand(s string, options ...func(*Config)) (*Hand, error) {
+	cards, err := ParseCards(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewErr(cards, options...)
+}
+
+// standard returns true if hands are selected by the standard high hand
+// rankings so that FastEval values can be used to compare them.  It must
+// be updated when options that change hand values are added.
+func (c *Config) standard() bool {
+	return c.sorting != SortingLow && !c.ignoreStraights && !c.ignoreFlushes &&
+		!c.aceIsLow && !c.ignoreLowStraight && !c.shortDeck && !c.aroundTheCorner &&
+		c.flushSize == 0 && c.isWild == nil
+}
+
+func newConfig(options []func(*Config)) *Config {
+	c := &Config{}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// DeuceToSevenLow configures NewHand to select the lowest hand in which
+// aces are high and straights and flushes are counted against the hand.
+// Unlike Low, the ace can't play low so A-2-3-4-5 is not a straight.
+func DeuceToSevenLow(c *Config) {
+	c.sorting = SortingLow
+	c.ignoreLowStraight = true
+}
+
+// NoWheel configures NewHand for house rules in which the ace can't play
+// low in a straight so A-2-3-4-5 is not a straight.
+func NoWheel(c *Config) {
+	c.ignoreLowStraight = true
+}
+
+// AroundTheCorner configures NewHand for novelty games in which straights
+// can wrap around the ace such as Q-K-A-2-3.  Wrapping straights are
+// ranked by their top card after the ace so K-A-2-3-4 is a straight four
+// high that loses to the wheel.
+func AroundTheCorner(c *Config) {
+	c.aroundTheCorner = true
+}
+
+// FlushSize configures NewHand for games in which n cards of the same suit
+// are a flush.  n must be between three and five.  The cards of a flush
+// are compared in order of rank regardless of their suits.  Straight
+// flushes and royal flushes still require five cards of the same suit.
+func FlushSize(n int) func(*Config) {
+	return func(c *Config) {
+		c.flushSize = n
+	}
+}
+
+// ShortDeck configures NewHand for short deck (six plus) games in which
+// flushes beat full houses and three of a kind beats a straight.  The ace
+// plays low in A-6-7-8-9, the lowest straight.  NewErr
+// returns an error if any card is ranked below six.
+func ShortDeck(c *Config) {
+	c.shortDeck = true
+}
+
+// RejectDuplicates configures NewErr to return an error if the same
+// card appears more than once.
+func RejectDuplicates(c *Config) {
+	c.rejectDuplicates = true
+}
+
 // A Hand is the highest poker hand derived from five or more cards.
 type Hand struct {
 	ranking     Ranking
 	cards       []*Card
 	description string
+	config      Config
 }
 
 // New forms a hand from the given cards and configuration
 // options.  If there are more than five cards, New will return
 // the winning hand out of all five card combinations.  If there are
 // less than five cards, blank cards will be inserted so that a value
-// can still be calculated.
+// can still be calculated.  Hands can be formed from at most MaxCards
+// cards.  New panics if given malformed cards, use
+// NewErr to receive an error instead.
 func New(cards []*Card, options ...func(*Config)) *Hand {
-	c := &Config{}
-	for _, option := range options {
-		option(c)
+	h, err := NewErr(cards, options...)
+	if err != nil {
+		panic(err)
 	}
+	return h
+}
 
-	combos := cardCombos(cards)
-	hands := []*Hand{}
-	for _, combo := range combos {
-		hand := handForFiveCards(combo, *c)
-		hands = append(hands, hand)
+// NewErr is the same as New except that it returns an error instead of
+// panicking if the cards are nil, have an invalid rank or suit, or can't
+// otherwise be ranked.
+func NewErr(cards []*Card, options ...func(*Config)) (*Hand, error) {
+	c := newConfig(options)
+	if err := validate(cards, c); err != nil {
+		return nil, err
+	}
+
+	// the best combination is found w/o forming a hand for each one
+	if len(cards) > 5 && c.standard() {
+		combo, ok := bestFastCombo(cards)
+		if !ok {
+			return nil, fmt.Errorf("hand: no ranking found for cards %v", cards)
+		}
+		return handForFiveCards(combo, *c)
 	}
 
+	var best *Hand
+	var err error
+	forEachCombo(cards, func(combo []*Card) {
+		if err != nil {
+			return
+		}
+		hand, hErr := handForFiveCards(combo, *c)
+		if hErr != nil {
+			err = hErr
+			return
+		}
+		if best == nil || isBetter(hand, best, c.sorting) {
+			best = hand
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+// BestHands returns the best n hands formed from five card combinations
+// of the cards in order from best to worst.  Combinations of the same
+// cards are only included once and fewer than n hands are returned if
+// there aren't enough combinations.  BestHands panics if given malformed
+// cards.
+func BestHands(cards []*Card, n int, options ...func(*Config)) []*Hand {
+	c := newConfig(options)
+	if err := validate(cards, c); err != nil {
+		panic(err)
+	}
+	hands := []*Hand{}
+	seen := map[string]bool{}
+	forEachCombo(cards, func(combo []*Card) {
+		strs := []string{}
+		for _, card := range combo {
+			strs = append(strs, card.String())
+		}
+		sort.Strings(strs)
+		key := strings.Join(strs, " ")
+		if !seen[key] {
+			seen[key] = true
+			hands = append(hands, New(append([]*Card{}, combo...), options...))
+		}
+	})
 	hands = Sort(c.sorting, DESC, hands...)
-	return hands[0]
+	if n < 0 {
+		n = 0
+	}
+	if n < len(hands) {
+		hands = hands[:n]
+	}
+	return hands
+}
+
+// validate returns an error if the cards can't form a hand with the
+// config.
+func validate(cards []*Card, c *Config) error {
+	for _, card := range cards {
+		if card == nil || !card.Rank().valid() || !card.Suit().valid() {
+			return fmt.Errorf("hand: invalid card in %v", cards)
+		}
+	}
+
+	if c.shortDeck {
+		for _, card := range cards {
+			if card.Rank().indexOf() < Six.indexOf() {
+				return fmt.Errorf("hand: card %v is not in a short deck", card)
+			}
+		}
+	}
+
+	if c.rejectDuplicates {
+		if card := duplicateCard(cards); card != nil {
+			return fmt.Errorf("hand: duplicate card %v in %v", card, cards)
+		}
+	}
+
+	if len(cards) > MaxCards {
+		return fmt.Errorf("hand: %d cards exceeds the maximum of %d", len(cards), MaxCards)
+	}
+
+	if c.flushSize != 0 && (c.flushSize < 3 || c.flushSize > 5) {
+		return fmt.Errorf("hand: invalid flush size %d", c.flushSize)
+	}
+	return nil
+}
+
+// MaxCards is the maximum number of cards a hand can be formed from.
+const MaxCards = 10
+
+// isBetter returns true if h is strictly better than o for the sorting.
+func isBetter(h, o *Hand, s Sorting) bool {
+	if s == SortingLow {
+		return h.CompareTo(o) < 0
+	}
+	return h.CompareTo(o) > 0
 }
 
 // Ranking returns the hand ranking of the hand.
@@ -139,8 +418,103 @@ func (h *Hand) Ranking() Ranking {
 }
 
 // Cards returns the five cards used in the best hand ranking for the hand.
+// The returned slice is a copy and may be modified by the caller.
 func (h *Hand) Cards() []*Card {
-	return h.cards
+	cards := make([]*Card, len(h.cards))
+	copy(cards, h.cards)
+	return cards
+}
+
+// IsPartial returns true if the hand was formed from fewer than five cards
+// and has blank cards in place of the missing ones.
+func (h *Hand) IsPartial() bool {
+	return hasBlankCards(h.cards)
+}
+
+// Ranks returns the ranks of the hand's cards in the same order as Cards.
+// Blank cards are never returned.
+func (h *Hand) Ranks() []Rank {
+	ranks := []Rank{}
+	for _, c := range h.cards {
+		if !hasBlankCards([]*Card{c}) {
+			ranks = append(ranks, c.Rank())
+		}
+	}
+	return ranks
+}
+
+// Suits returns the suits of the hand's cards in the same order as Cards.
+// Blank cards are never returned.
+func (h *Hand) Suits() []Suit {
+	suits := []Suit{}
+	for _, c := range h.cards {
+		if !hasBlankCards([]*Card{c}) {
+			suits = append(suits, c.Suit())
+		}
+	}
+	return suits
+}
+
+// Kickers returns the cards that don't make up the hand's ranking but
+// are used to break ties, in descending order.  Straights, flushes, and
+// full houses have no kickers.  Blank cards are never returned.
+func (h *Hand) Kickers() []*Card {
+	n := 0
+	switch h.Ranking() {
+	case HighCard:
+		n = 1
+	case Pair:
+		n = 2
+	case ThreeOfAKind:
+		n = 3
+	case TwoPair, FourOfAKind:
+		n = 4
+	default:
+		return []*Card{}
+	}
+	kickers := []*Card{}
+	for _, c := range h.cards[n:] {
+		if !hasBlankCards([]*Card{c}) {
+			kickers = append(kickers, c)
+		}
+	}
+	return kickers
+}
+
+// UsesCard returns true if the card is one of the five cards selected
+// for the hand.  Cards are compared with Card.Equal, so the card doesn't
+// need to be the same pointer that formed the hand.
+func (h *Hand) UsesCard(c *Card) bool {
+	for _, card := range h.cards {
+		if card.Equal(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Improve returns the best hand formed from the hand's cards and the
+// given card using the same options as the hand.  The hand itself is
+// unchanged.  Improve panics if the card is malformed.
+func (h *Hand) Improve(c *Card) *Hand {
+	cards := []*Card{c}
+	for _, card := range h.cards {
+		if !hasBlankCards([]*Card{card}) {
+			cards = append(cards, card)
+		}
+	}
+	return New(cards, func(c *Config) { *c = h.config })
+}
+
+// Clone returns a deep copy of the hand that shares no cards with it.
+func (h *Hand) Clone() *Hand {
+	clone := *h
+	clone.cards = make([]*Card, len(h.cards))
+	for i, c := range h.cards {
+		card := *c
+		clone.cards[i] = &card
+	}
+	return &clone
 }
 
 // Description returns a user displayable description of the hand such as
@@ -149,62 +523,392 @@ func (h *Hand) Description() string {
 	return h.description
 }
 
+// DescriptionWithKicker returns the description of two pair hands
+// followed by the kicker such as "two pair aces and jacks, king kicker".
+// Other hands are described the same as Description.
+func (h *Hand) DescriptionWithKicker() string {
+	if h.ranking != TwoPair || hasBlankCards(h.cards[4:]) {
+		return h.description
+	}
+	return fmt.Sprintf(localizer.KickerFormat(), h.description, h.cards[4].Rank().singularName())
+}
+
+// VerboseDescription returns the description followed by the suit of
+// flushes, straight flushes, and royal flushes such as "flush ace high in
+// spades".  Other hands are described the same as Description.
+func (h *Hand) VerboseDescription() string {
+	switch h.ranking {
+	case Flush, StraightFlush, RoyalFlush:
+		return fmt.Sprintf(localizer.SuitFormat(), h.description, localizer.SuitName(h.cards[0].Suit()))
+	}
+	return h.description
+}
+
+// ShortDescription returns a compact description of the hand made of the
+// ranking's abbreviation followed by the ranks of the hand's cards in
+// order of importance, such as "FH KKK66" for kings full of sixes.  The
+// abbreviations are HC (high card), 1P (pair), 2P (two pair), 3K (three
+// of a kind), ST (straight), FL (flush), FH (full house), 4K (four of a
+// kind), SF (straight flush), RF (royal flush), and 5K (five of a kind).
+func (h *Hand) ShortDescription() string {
+	ranks := ""
+	for _, c := range h.cards {
+		if !hasBlankCards([]*Card{c}) {
+			ranks += string(c.Rank())
+		}
+	}
+	return shortNames[h.ranking] + " " + ranks
+}
+
+var shortNames = map[Ranking]string{
+	HighCard:      "HC",
+	Pair:          "1P",
+	TwoPair:       "2P",
+	ThreeOfAKind:  "3K",
+	Straight:      "ST",
+	Flush:         "FL",
+	FullHouse:     "FH",
+	FourOfAKind:   "4K",
+	StraightFlush: "SF",
+	RoyalFlush:    "RF",
+	FiveOfAKind:   "5K",
+}
+
 // String returns the description followed by the cards used.
 func (h *Hand) String() string {
-	return fmt.Sprintf("%s %v", h.Description(), h.Cards())
+	return fmt.Sprintf("%s %v", h.Description(), h.cards)
 }
 
 // CompareTo returns a positive value if this hand beats the other hand, a
 // negative value if this hand loses to the other hand, and zero if the hands
-// are equal.
+// are equal.  If the hand was formed with aces low, aces are ranked
+// below twos.
 func (h *Hand) CompareTo(o *Hand) int {
+	cmp, _ := h.CompareDetail(o)
+	return cmp
+}
+
+// CompareDetail returns the same value as CompareTo along with the index
+// of the card that decided the comparison.  The index is -1 if the hands
+// are equal or were decided by their rankings.
+func (h *Hand) CompareDetail(o *Hand) (int, int) {
 	if h.Ranking() != o.Ranking() {
-		return int(h.Ranking()) - int(o.Ranking())
+		return rankingValue(h.Ranking(), h.config) - rankingValue(o.Ranking(), h.config), -1
+	}
+	indexOf := Rank.indexOf
+	if h.config.aceIsLow {
+		indexOf = Rank.aceLowIndexOf
 	}
-	hCards := h.Cards()
-	oCards := o.Cards()
+	hCards := h.cards
+	oCards := o.cards
 	for i := 0; i < 5; i++ {
 		hCard, oCard := hCards[i], oCards[i]
-		hIndex, oIndex := hCard.Rank().indexOf(), oCard.Rank().indexOf()
+		hIndex, oIndex := indexOf(hCard.Rank()), indexOf(oCard.Rank())
 		if hIndex != oIndex {
-			return hIndex - oIndex
+			return hIndex - oIndex, i
+		}
+	}
+	return 0, -1
+}
+
+// MarginDescription describes why the better of the two hands wins based
+// on CompareDetail, such as "wins with a higher pair" or "wins with a
+// better kicker".  Hands that tie are described as "split pot".  The
+// description is always in English since it isn't provided by the
+// Localizer.
+func MarginDescription(h, o *Hand) string {
+	cmp, i := h.CompareDetail(o)
+	if cmp == 0 {
+		return "split pot"
+	}
+	winner, loser := h, o
+	if cmp < 0 {
+		winner, loser = o, h
+	}
+	if i < 0 {
+		return fmt.Sprintf("wins with a better hand, %v over %v",
+			rankingNames[winner.Ranking()], rankingNames[loser.Ranking()])
+	}
+	return "wins with " + marginPart(winner.Ranking(), i)
+}
+
+// marginPart names the part of a hand of the ranking that the card at
+// index i belongs to.
+func marginPart(r Ranking, i int) string {
+	switch r {
+	case HighCard:
+		if i == 0 {
+			return "a higher card"
+		}
+	case Pair:
+		if i < 2 {
+			return "a higher pair"
+		}
+	case TwoPair:
+		if i < 2 {
+			return "a higher top pair"
+		} else if i < 4 {
+			return "a higher second pair"
+		}
+	case ThreeOfAKind:
+		if i < 3 {
+			return "a higher three of a kind"
+		}
+	case FullHouse:
+		if i < 3 {
+			return "a higher three of a kind"
+		}
+		return "a higher pair"
+	case FourOfAKind:
+		if i < 4 {
+			return "a higher four of a kind"
+		}
+	default:
+		return "a higher " + rankingNames[r]
+	}
+	return "a better kicker"
+}
+
+// CompareToSuited is the same as CompareTo except that hands that tie are
+// compared by the suits of their cards in order, with spades ranked above
+// hearts, hearts above diamonds, and diamonds above clubs.  Hands only tie
+// if their cards have the same ranks and suits.
+func (h *Hand) CompareToSuited(o *Hand) int {
+	if cmp := h.CompareTo(o); cmp != 0 {
+		return cmp
+	}
+	hSuits, oSuits := suitIndexes(h.cards), suitIndexes(o.cards)
+	for i := range hSuits {
+		if hSuits[i] != oSuits[i] {
+			return oSuits[i] - hSuits[i]
 		}
 	}
 	return 0
 }
 
+// suitIndexes returns the suit indexes of the cards with the indexes of
+// cards of the same rank sorted so that their order doesn't matter.
+func suitIndexes(cards []*Card) []int {
+	indexes := []int{}
+	for i, c := range cards {
+		indexes = append(indexes, c.Suit().indexOf())
+		for j := i; j > 0 && cards[j-1].Rank() == c.Rank() && indexes[j-1] > indexes[j]; j-- {
+			indexes[j-1], indexes[j] = indexes[j], indexes[j-1]
+		}
+	}
+	return indexes
+}
+
+// Key returns a value that is the same for hands that tie according to
+// CompareTo and different for hands that don't, regardless of the order
+// and suits of the cards the hands were formed from.  Key is intended for
+// caching and only compares hands formed with the same options.
+func (h *Hand) Key() uint64 {
+	key := uint64(h.ranking)
+	for _, c := range h.cards {
+		index := c.Rank().indexOf()
+		if index == -1 {
+			// blank cards
+			index = len(allRanks())
+		}
+		key = key<<4 | uint64(index)
+	}
+	return key
+}
+
+// Beats returns true if this hand beats the other hand.
+func (h *Hand) Beats(o *Hand) bool {
+	return h.CompareTo(o) > 0
+}
+
+// Ties returns true if this hand is equal to the other hand.
+func (h *Hand) Ties(o *Hand) bool {
+	return h.CompareTo(o) == 0
+}
+
+// LosesTo returns true if this hand loses to the other hand.
+func (h *Hand) LosesTo(o *Hand) bool {
+	return h.CompareTo(o) < 0
+}
+
+// rankingValue returns the relative value of the ranking for comparison.
+// Short deck swaps the value of flushes and full houses as well as three
+// of a kind and straights.
+func rankingValue(r Ranking, c Config) int {
+	if c.shortDeck {
+		switch r {
+		case ThreeOfAKind:
+			return int(Straight)
+		case Straight:
+			return int(ThreeOfAKind)
+		case Flush:
+			return int(FullHouse)
+		case FullHouse:
+			return int(Flush)
+		}
+	}
+	return int(r)
+}
+
+// ErrJSONWild is returned by MarshalJSON for hands formed with wild cards
+// or bugs since the function choosing them can't be encoded.
+var ErrJSONWild = errors.New("hand: can't marshal a hand formed with wild cards to json")
+
+// VerifyJSONRanking controls whether UnmarshalJSON returns an error when
+// the ranking stored in the json doesn't match the ranking of its cards.
+// Set it to false to trust the cards alone.
+var VerifyJSONRanking = true
+
+// handJSON is the json representation of a hand.
+type handJSON struct {
+	Ranking     Ranking `json:"ranking"`
+	Cards       []*Card `json:"cards"`
+	Description string  `json:"description"`
+	Options     int     `json:"options,omitempty"`
+	FlushSize   int     `json:"flushSize,omitempty"`
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 // The json format is:
-// {"ranking":9,"cards":["A♠","K♠","Q♠","J♠","T♠"],"description":"royal flush"}
+// {"ranking":10,"cards":["A♠","K♠","Q♠","J♠","T♠"],"description":"royal flush"}
+// Hands formed with options also store the option flags of the binary
+// format and the flush size so they round trip.  MarshalJSON returns
+// ErrJSONWild if the hand was formed with wild cards.
 func (h *Hand) MarshalJSON() ([]byte, error) {
-	cards := h.Cards()
-	b, err := json.Marshal(&cards)
-	if err != nil {
-		return []byte{}, err
+	if h.config.isWild != nil {
+		return nil, ErrJSONWild
 	}
-	const format = `{"ranking":%v,"cards":%v,"description":"%v"}`
-	s := fmt.Sprintf(format, h.Ranking(), string(b), h.Description())
-	return []byte(s), nil
+	return json.Marshal(&handJSON{
+		Ranking:     h.Ranking(),
+		Cards:       h.cards,
+		Description: h.Description(),
+		Options:     int(optionFlags(&h.config)),
+		FlushSize:   h.config.flushSize,
+	})
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
-//  The json format is:
-// {"ranking":9,"cards":["A♠","K♠","Q♠","J♠","T♠"],"description":"royal flush"}
+// The json format is:
+// {"ranking":10,"cards":["A♠","K♠","Q♠","J♠","T♠"],"description":"royal flush"}
+// The hand is recomputed from the cards and any stored options.  If
+// VerifyJSONRanking is true and a ranking is present that doesn't match
+// the cards, an error is returned.
 func (h *Hand) UnmarshalJSON(b []byte) error {
-	type handJSON struct {
-		Cards []*Card
-	}
 	m := &handJSON{}
 	if err := json.Unmarshal(b, m); err != nil {
 		return err
 	}
-	newHand := New(m.Cards)
-	h.cards = newHand.Cards()
-	h.ranking = newHand.Ranking()
-	h.description = newHand.Description()
+	if m.Options < 0 || m.Options > 0xff || m.FlushSize < 0 || m.FlushSize > 0xff {
+		return fmt.Errorf("hand: invalid json options %d and flush size %d", m.Options, m.FlushSize)
+	}
+	newHand, err := NewErr(m.Cards, flagOptions(byte(m.Options), byte(m.FlushSize)))
+	if err != nil {
+		return err
+	}
+	if VerifyJSONRanking && m.Ranking != 0 && m.Ranking != newHand.Ranking() {
+		const format = "hand: json ranking %v doesn't match ranking %v of cards %v"
+		return fmt.Errorf(format, m.Ranking, newHand.Ranking(), m.Cards)
+	}
+	*h = *newHand
 	return nil
 }
 
+// ErrBinaryWild is returned by MarshalBinary for hands formed with wild
+// cards or bugs since the function choosing them can't be encoded.
+var ErrBinaryWild = errors.New("hand: can't marshal a hand formed with wild cards to binary")
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The binary format is eight bytes: the ranking, one byte for each of the
+// hand's five cards, a byte of option flags, and the flush size.  A card
+// is encoded as its ID plus one.  Blank cards in hands formed from fewer
+// than five cards are encoded as zero.  The flags record the options that
+// change how hands are ranked so hands formed with options such as
+// AceToFiveLow or ShortDeck round trip.  MarshalBinary returns
+// ErrBinaryWild if the hand was formed with wild cards.
+func (h *Hand) MarshalBinary() ([]byte, error) {
+	if h.config.isWild != nil {
+		return nil, ErrBinaryWild
+	}
+	b := make([]byte, 1, 8)
+	b[0] = byte(h.Ranking())
+	for _, c := range h.cards {
+		if hasBlankCards([]*Card{c}) {
+			b = append(b, 0)
+			continue
+		}
+		b = append(b, byte(c.ID()+1))
+	}
+	return append(b, optionFlags(&h.config), byte(h.config.flushSize)), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface for
+// the format written by MarshalBinary.  The hand is recomputed from the
+// cards and options and an error is returned if its ranking doesn't match
+// the stored ranking.
+func (h *Hand) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("hand: binary hand should be 8 bytes not %d", len(data))
+	}
+	cards := []*Card{}
+	for _, b := range data[1:6] {
+		if b == 0 {
+			continue
+		}
+		if int(b) > len(cardsByID) {
+			return fmt.Errorf("hand: invalid binary card %d", b)
+		}
+		cards = append(cards, CardFromID(int(b)-1))
+	}
+	newHand, err := NewErr(cards, flagOptions(data[6], data[7]))
+	if err != nil {
+		return err
+	}
+	if Ranking(data[0]) != newHand.Ranking() {
+		const format = "hand: binary ranking %v doesn't match ranking %v of cards %v"
+		return fmt.Errorf(format, Ranking(data[0]), newHand.Ranking(), cards)
+	}
+	*h = *newHand
+	return nil
+}
+
+// binaryFlags returns the config's boolean options in the order of their
+// bits in the binary format.  The order must not change.
+func binaryFlags(c *Config) []*bool {
+	return []*bool{&c.ignoreStraights, &c.ignoreFlushes, &c.aceIsLow,
+		&c.ignoreLowStraight, &c.shortDeck, &c.aroundTheCorner, &c.rejectDuplicates}
+}
+
+// binaryLowFlag is the bit of the binary option flags set for low hands.
+const binaryLowFlag = 1 << 7
+
+// optionFlags returns the binary option flags of the config.
+func optionFlags(c *Config) byte {
+	flags := byte(0)
+	for i, set := range binaryFlags(c) {
+		if *set {
+			flags |= 1 << uint(i)
+		}
+	}
+	if c.sorting == SortingLow {
+		flags |= binaryLowFlag
+	}
+	return flags
+}
+
+// flagOptions returns the option that restores the config encoded by
+// optionFlags and the flush size.
+func flagOptions(flags, flushSize byte) func(*Config) {
+	return func(c *Config) {
+		for i, set := range binaryFlags(c) {
+			*set = flags&(1<<uint(i)) != 0
+		}
+		if flags&binaryLowFlag != 0 {
+			c.sorting = SortingLow
+		}
+		c.flushSize = int(flushSize)
+	}
+}
+
 // Sort returns a list of hands sorted by the given sorting
 func Sort(s Sorting, o Ordering, hands ...*Hand) []*Hand {
 	handsCopy := make([]*Hand, len(hands))
@@ -212,59 +916,196 @@ func Sort(s Sorting, o Ordering, hands ...*Hand) []*Hand {
 
 	high := (o == ASC && s == SortingHigh) || (o == DESC && s == SortingLow)
 	if high {
-		sort.Sort(byHighHand(handsCopy))
+		sort.Sort(ByHighHand(handsCopy))
 	} else {
-		sort.Sort(sort.Reverse(byHighHand(handsCopy)))
+		sort.Sort(ByLowHand(handsCopy))
 	}
 
 	return handsCopy
 }
 
+// Winners returns every hand that ties for the best hand in the order
+// they were given.  Hands formed with a low sorting option such as Low
+// or AceToFiveLow are judged by the lowest hand.
+func Winners(hands []*Hand) []*Hand {
+	winners := []*Hand{}
+	for _, i := range WinnerIndices(hands) {
+		winners = append(winners, hands[i])
+	}
+	return winners
+}
+
+// WinnerIndices is the same as Winners except that it returns the
+// indexes of the winning hands.
+func WinnerIndices(hands []*Hand) []int {
+	indices := []int{}
+	if len(hands) == 0 {
+		return indices
+	}
+	best := Sort(hands[0].config.sorting, DESC, hands...)[0]
+	for i, h := range hands {
+		if h.Ties(best) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// SortHands sorts the hands in place by CompareTo.  SortingHigh sorts the
+// strongest hand first and SortingLow sorts the weakest hand first.
+func SortHands(hands []*Hand, s Sorting) {
+	if s == SortingLow {
+		sort.Sort(ByHighHand(hands))
+		return
+	}
+	sort.Sort(ByLowHand(hands))
+}
+
 // ByHighHand is a slice of hands sort in ascending value
-type byHighHand []*Hand
+type ByHighHand []*Hand
 
 // Len implements the sort.Interface interface.
-func (a byHighHand) Len() int { return len(a) }
+func (a ByHighHand) Len() int { return len(a) }
 
 // Swap implements the sort.Interface interface.
-func (a byHighHand) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ByHighHand) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 
 // Less implements the sort.Interface interface.
-func (a byHighHand) Less(i, j int) bool {
+func (a ByHighHand) Less(i, j int) bool {
 	iHand, jHand := a[i], a[j]
 	return iHand.CompareTo(jHand) < 0
 }
 
-func handForFiveCards(cards []*Card, c Config) *Hand {
-	cards = formCards(cards, c)
+// ByLowHand is a slice of hands sorted in descending value, the reverse
+// of ByHighHand.
+type ByLowHand []*Hand
+
+// Len implements the sort.Interface interface.
+func (a ByLowHand) Len() int { return len(a) }
+
+// Swap implements the sort.Interface interface.
+func (a ByLowHand) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+
+// Less implements the sort.Interface interface.
+func (a ByLowHand) Less(i, j int) bool {
+	return ByHighHand(a).Less(j, i)
+}
+
+func handForFiveCards(cards []*Card, c Config) (*Hand, error) {
+	if c.isWild != nil {
+		return wildHandForFiveCards(cards, c)
+	}
+	return rankedHand(cards, c)
+}
+
+func rankedHand(cards []*Card, c Config) (*Hand, error) {
+	h := &Hand{}
+	if err := h.rank(formCards(cards, c), c); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// rank sets the hand to the best ranking of the cards formed by
+// formCards.  The hand keeps the formed cards.
+func (h *Hand) rank(formed []*Card, c Config) error {
 	for _, r := range rankings {
-		if r.vFunc(cards, c) {
-			return &Hand{
+		if r.vFunc(formed, c) {
+			if r.r == Flush && !hasFlush(formed) {
+				formed = formShortFlush(formed, c)
+			}
+			*h = Hand{
 				ranking:     r.r,
-				cards:       cards,
-				description: r.dFunc(cards),
+				cards:       formed,
+				description: r.dFunc(formed, c),
+				config:      c,
 			}
+			return nil
+		}
+	}
+	return fmt.Errorf("hand: no ranking found for cards %v", formed)
+}
+
+// Is returns true if the five cards form a hand of the ranking using the
+// given configuration options.  Is doesn't form a Hand unless the options
+// include wild cards.  Is returns false if not given five valid cards.
+func Is(cards []*Card, r Ranking, options ...func(*Config)) bool {
+	if len(cards) != 5 {
+		return false
+	}
+	for _, card := range cards {
+		if card == nil || !card.Rank().valid() || !card.Suit().valid() {
+			return false
 		}
 	}
-	panic("unreachable")
+	c := newConfig(options)
+	if c.isWild != nil {
+		h, err := wildHandForFiveCards(append([]*Card{}, cards...), *c)
+		return err == nil && h.Ranking() == r
+	}
+	formed := formCards(append([]*Card{}, cards...), *c)
+	for _, rk := range rankings {
+		if rk.r == r {
+			return rk.vFunc(formed, *c)
+		}
+	}
+	return false
 }
 
 func cardCombos(cards []*Card) [][]*Card {
 	cCombo := [][]*Card{}
+	forEachCombo(cards, func(combo []*Card) {
+		cCards := make([]*Card, len(combo))
+		copy(cCards, combo)
+		cCombo = append(cCombo, cCards)
+	})
+	return cCombo
+}
+
+// forEachCombo calls fn with each combination of five cards or, if there
+// are less than five cards, with all of the cards.  The slice passed to
+// fn is reused between calls so fn must not retain it.
+func forEachCombo(cards []*Card, fn func([]*Card)) {
+	// a single empty combo is filled w/ blank cards by formCards
+	if len(cards) == 0 {
+		fn([]*Card{})
+		return
+	}
+
 	l := 5
 	if len(cards) < 5 {
 		l = len(cards)
 	}
-	indexCombos := util.Combinations(len(cards), l)
-
-	for _, combo := range indexCombos {
-		cCards := []*Card{}
-		for _, i := range combo {
-			cCards = append(cCards, cards[i])
+	buf := make([]*Card, l)
+	for _, combo := range combinations(len(cards), l) {
+		for j, i := range combo {
+			buf[j] = cards[i]
 		}
-		cCombo = append(cCombo, cCards)
+		fn(buf)
 	}
-	return cCombo
+}
+
+var (
+	combosMu sync.RWMutex
+	combos   = map[[2]int][][]int{}
+)
+
+// combinations returns util.Combinations(n, k) from a cache.  The
+// returned slices are shared and must not be modified.
+func combinations(n, k int) [][]int {
+	key := [2]int{n, k}
+	combosMu.RLock()
+	c, ok := combos[key]
+	combosMu.RUnlock()
+	if ok {
+		return c
+	}
+
+	c = util.Combinations(n, k)
+	combosMu.Lock()
+	combos[key] = c
+	combosMu.Unlock()
+	return c
 }
 
 type ranking struct {
@@ -274,14 +1115,14 @@ type ranking struct {
 }
 
 type validFunc func([]*Card, Config) bool
-type descFunc func([]*Card) string
+type descFunc func([]*Card, Config) string
 
 var (
 	highCard = ranking{
 		r: HighCard,
 		vFunc: func(cards []*Card, c Config) bool {
-			flush := hasFlush(cards)
-			straight := hasStraight(cards)
+			flush := hasFlush(cards) || hasShortFlush(cards, c)
+			straight := hasStraight(cards, c)
 			pairs := hasPairs(cards, []int{1, 1, 1, 1, 1})
 			if !c.ignoreStraights {
 				pairs = pairs && !straight
@@ -291,43 +1132,46 @@ var (
 			}
 			return pairs
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r := cards[0].Rank()
-			return fmt.Sprintf("high card %v high", r.singularName())
+			if c.aceIsLow {
+				return fmt.Sprintf(localizer.LowFormat(), r.singularName())
+			}
+			return fmt.Sprintf(localizer.Format(HighCard), r.singularName())
 		},
 	}
 
 	pair = ranking{
 		r: Pair,
 		vFunc: func(cards []*Card, c Config) bool {
-			return hasPairs(cards, []int{2, 2, 1, 1, 1})
+			return hasPairs(cards, []int{2, 2, 1, 1, 1}) && !hasShortFlush(cards, c)
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r := cards[0].Rank()
-			return fmt.Sprintf("pair of %v", r.pluralName())
+			return fmt.Sprintf(localizer.Format(Pair), r.pluralName())
 		},
 	}
 
 	twoPair = ranking{
 		r: TwoPair,
 		vFunc: func(cards []*Card, c Config) bool {
-			return hasPairs(cards, []int{2, 2, 2, 2, 1})
+			return hasPairs(cards, []int{2, 2, 2, 2, 1}) && !hasShortFlush(cards, c)
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r1 := cards[0].Rank()
 			r2 := cards[2].Rank()
-			return fmt.Sprintf("two pair %v and %v", r1.pluralName(), r2.pluralName())
+			return fmt.Sprintf(localizer.Format(TwoPair), r1.pluralName(), r2.pluralName())
 		},
 	}
 
 	threeOfAKind = ranking{
 		r: ThreeOfAKind,
 		vFunc: func(cards []*Card, c Config) bool {
-			return hasPairs(cards, []int{3, 3, 3, 1, 1})
+			return hasPairs(cards, []int{3, 3, 3, 1, 1}) && !hasShortFlush(cards, c)
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r := cards[0].Rank()
-			return fmt.Sprintf("three of a kind %v", r.pluralName())
+			return fmt.Sprintf(localizer.Format(ThreeOfAKind), r.pluralName())
 		},
 	}
 
@@ -337,13 +1181,13 @@ var (
 			if c.ignoreStraights {
 				return false
 			}
-			flush := hasFlush(cards)
-			straight := hasStraight(cards)
+			flush := hasFlush(cards) || hasShortFlush(cards, c)
+			straight := hasStraight(cards, c)
 			return !flush && straight
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r := cards[0].Rank()
-			return fmt.Sprintf("straight %v high", r.singularName())
+			return fmt.Sprintf(localizer.Format(Straight), r.singularName())
 		},
 	}
 
@@ -355,12 +1199,12 @@ var (
 			}
 
 			flush := hasFlush(cards)
-			straight := hasStraight(cards)
-			return flush && !straight
+			straight := hasStraight(cards, c)
+			return (flush && !straight) || (!flush && hasShortFlush(cards, c))
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r1 := cards[0].Rank()
-			return fmt.Sprintf("flush %v high", r1.singularName())
+			return fmt.Sprintf(localizer.Format(Flush), r1.singularName())
 		},
 	}
 
@@ -369,10 +1213,10 @@ var (
 		vFunc: func(cards []*Card, c Config) bool {
 			return hasPairs(cards, []int{3, 3, 3, 2, 2})
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r1 := cards[0].Rank()
 			r2 := cards[3].Rank()
-			return fmt.Sprintf("full house %v full of %v", r1.pluralName(), r2.pluralName())
+			return fmt.Sprintf(localizer.Format(FullHouse), r1.pluralName(), r2.pluralName())
 		},
 	}
 
@@ -381,9 +1225,9 @@ var (
 		vFunc: func(cards []*Card, c Config) bool {
 			return hasPairs(cards, []int{4, 4, 4, 4, 1})
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r := cards[0].Rank()
-			return fmt.Sprintf("four of a kind %v", r.pluralName())
+			return fmt.Sprintf(localizer.Format(FourOfAKind), r.pluralName())
 		},
 	}
 
@@ -394,12 +1238,12 @@ var (
 				return false
 			}
 			flush := hasFlush(cards)
-			straight := hasStraight(cards)
+			straight := hasStraight(cards, c)
 			return cards[0].Rank() != Ace && flush && straight
 		},
-		dFunc: func(cards []*Card) string {
+		dFunc: func(cards []*Card, c Config) string {
 			r := cards[0].Rank()
-			return fmt.Sprintf("straight flush %v high", r.singularName())
+			return fmt.Sprintf(localizer.Format(StraightFlush), r.singularName())
 		},
 	}
 
@@ -410,41 +1254,60 @@ var (
 				return false
 			}
 			flush := hasFlush(cards)
-			straight := hasStraight(cards)
+			straight := hasStraight(cards, c)
 			return cards[0].Rank() == Ace && flush && straight
 		},
-		dFunc: func(cards []*Card) string {
-			return "royal flush"
+		dFunc: func(cards []*Card, c Config) string {
+			return localizer.Format(RoyalFlush)
 		},
 	}
 
-	rankings = []ranking{highCard, pair, twoPair, threeOfAKind,
+	fiveOfAKind = ranking{
+		r: FiveOfAKind,
+		vFunc: func(cards []*Card, c Config) bool {
+			return c.isWild != nil && hasPairs(cards, []int{5, 5, 5, 5, 5})
+		},
+		dFunc: func(cards []*Card, c Config) string {
+			r := cards[0].Rank()
+			return fmt.Sprintf(localizer.Format(FiveOfAKind), r.pluralName())
+		},
+	}
+
+	// five of a kind is checked first because wild cards can form five
+	// cards of the same rank that also share a suit
+	rankings = []ranking{fiveOfAKind, highCard, pair, twoPair, threeOfAKind,
 		straight, flush, fullHouse, fourOfAKind, straightFlush, royalFlush}
 )
 
 func formCards(cards []*Card, c Config) []*Card {
+	return formCardsInto(make([]*Card, 0, 5), cards, c)
+}
+
+// formCardsInto is the same as formCards except that the formed cards
+// reuse buf if it has room.  The cards are sorted in place.
+func formCardsInto(buf []*Card, cards []*Card, c Config) []*Card {
 	var ranks []Rank
 	if c.aceIsLow {
 		// sort cards staring w/ king
-		sort.Sort(sort.Reverse(byAceLow(cards)))
-		// sort ranks starting w/ king
-		ranks = allRanks()
-		sort.Sort(sort.Reverse(byAceLowRank(ranks)))
+		sortDesc(cards, Rank.aceLowIndexOf)
+		ranks = aceLowRanksDesc
 	} else {
 		// sort cards staring w/ ace
-		sort.Sort(sort.Reverse(byAceHigh(cards)))
-		// sort ranks starting w/ ace
-		ranks = allRanks()
-		sort.Sort(sort.Reverse(byAceHighRank(ranks)))
+		sortDesc(cards, Rank.indexOf)
+		ranks = aceHighRanksDesc
 	}
 
 	// form cards starting w/ most paired
-	formed := []*Card{}
-	for i := 4; i > 0; i-- {
+	formed := buf[:0]
+	for i := 5; i > 0; i-- {
 		for _, r := range ranks {
-			rCards := cardsForRank(cards, r)
-			if len(rCards) == i {
-				formed = append(formed, rCards...)
+			if countRank(cards, r) != i {
+				continue
+			}
+			for _, c := range cards {
+				if c.Rank() == r {
+					formed = append(formed, c)
+				}
 			}
 		}
 	}
@@ -455,52 +1318,162 @@ func formCards(cards []*Card, c Config) []*Card {
 		formed = append(formed, &Card{rank: Rank(s), suit: Suit(s)})
 	}
 	// check for low straight
-	return formLowStraight(formed)
+	if c.ignoreLowStraight {
+		return formed
+	}
+	formed = formLowStraight(formed)
+	if c.shortDeck {
+		formed = formShortDeckLowStraight(formed)
+	}
+	if c.aroundTheCorner {
+		return formWrapStraight(formed)
+	}
+	return formed
+}
+
+// sortDesc sorts the few cards of a combination from highest to lowest
+// rank with an insertion sort, which unlike sort.Sort doesn't allocate.
+// Cards of the same rank keep their order.
+func sortDesc(cards []*Card, indexOf func(Rank) int) {
+	for i := 1; i < len(cards); i++ {
+		for j := i; j > 0 && indexOf(cards[j-1].Rank()) < indexOf(cards[j].Rank()); j-- {
+			cards[j-1], cards[j] = cards[j], cards[j-1]
+		}
+	}
 }
 
 func hasPairs(cards []*Card, pairNums []int) bool {
 	for i := 0; i < 5; i++ {
 		card := cards[i]
 		num := pairNums[i]
-		if num != len(cardsForRank(cards, card.Rank())) {
+		if num != countRank(cards, card.Rank()) {
 			return false
 		}
 	}
 	return true
 }
 
+// hasFlush returns true if at least five of the cards share a suit.  The
+// order of the cards doesn't matter.
 func hasFlush(cards []*Card) bool {
-	if hasBlankCards(cards) {
+	return maxSuitCount(cards) >= 5
+}
+
+// hasShortFlush returns true if the config's flush size is less than five
+// and enough cards share a suit to make a flush.
+func hasShortFlush(cards []*Card, c Config) bool {
+	if c.flushSize == 0 || c.flushSize >= 5 || c.ignoreFlushes {
 		return false
 	}
-	suit := cards[0].Suit()
-	has := true
-	for _, c := range cards {
-		has = has && c.Suit() == suit
+	return maxSuitCount(cards) >= c.flushSize
+}
+
+// maxSuitCount returns the number of cards of the most common suit.  Blank
+// cards aren't counted.
+func maxSuitCount(cards []*Card) int {
+	var counts [4]int
+	max := 0
+	for _, card := range cards {
+		i := card.Suit().indexOf()
+		if i >= len(counts) {
+			continue
+		}
+		counts[i]++
+		if counts[i] > max {
+			max = counts[i]
+		}
+	}
+	return max
+}
+
+// formShortFlush arranges the cards of a short flush with the cards of
+// the flush's suit first followed by the kickers, each from highest to
+// lowest rank, so that short flushes are compared by their flush cards.
+func formShortFlush(cards []*Card, c Config) []*Card {
+	var counts [4]int
+	for _, card := range cards {
+		if i := card.Suit().indexOf(); i < len(counts) {
+			counts[i]++
+		}
+	}
+	suit := Suit("")
+	for i, n := range counts {
+		if n >= c.flushSize {
+			suit = allSuits()[i]
+		}
+	}
+
+	sorted := append([]*Card{}, cards...)
+	if c.aceIsLow {
+		sort.Stable(sort.Reverse(byAceLow(sorted)))
+	} else {
+		sort.Stable(sort.Reverse(byAceHigh(sorted)))
+	}
+	formed := make([]*Card, 0, len(cards))
+	for _, card := range sorted {
+		if card.Suit() == suit {
+			formed = append(formed, card)
+		}
+	}
+	for _, card := range sorted {
+		if card.Suit() != suit && !hasBlankCards([]*Card{card}) {
+			formed = append(formed, card)
+		}
 	}
-	return has
+	for _, card := range cards {
+		if hasBlankCards([]*Card{card}) {
+			formed = append(formed, card)
+		}
+	}
+	return formed
 }
 
-func hasStraight(cards []*Card) bool {
+// hasStraight returns true if the cards are five consecutive ranks.  The
+// ranks are checked with a bitmask so the order of the cards doesn't
+// matter.
+func hasStraight(cards []*Card, c Config) bool {
 	if hasBlankCards(cards) {
 		return false
 	}
-	lastIndex := cards[0].Rank().indexOf()
-	straight := true
-	for i := 1; i < 5; i++ {
-		index := cards[i].Rank().indexOf()
-		straight = straight && (lastIndex == index+1)
-		lastIndex = index
+	m := rankMask(cards)
+	if bits.OnesCount16(m) != 5 {
+		return false
+	}
+	ace := m >> aceIndex & 1
+	high := m
+	if c.aceIsLow {
+		high &^= 1 << aceIndex
 	}
-	return straight || hasLowStraight(cards)
+	// the wheel is a run once the ace is duplicated below the two
+	low := (m&^(1<<aceIndex))<<1 | ace
+	// straights around the corner are runs of the mask repeated
+	wrap := uint32(m) | uint32(m)<<uint(len(allRanks()))
+	return hasRun(uint32(high)) ||
+		(!c.ignoreLowStraight && hasRun(uint32(low))) ||
+		(c.shortDeck && !c.ignoreLowStraight && m == shortDeckLowStraightMask) ||
+		(c.aroundTheCorner && !c.ignoreLowStraight && hasRun(wrap))
 }
 
-func hasLowStraight(cards []*Card) bool {
-	return cards[0].Rank() == Five &&
-		cards[1].Rank() == Four &&
-		cards[2].Rank() == Three &&
-		cards[3].Rank() == Two &&
-		cards[4].Rank() == Ace
+// aceIndex is the index of the ace in allRanks.
+const aceIndex = 12
+
+// shortDeckLowStraightMask is the rank mask of A-6-7-8-9, the lowest
+// straight in a short deck.
+const shortDeckLowStraightMask = 1<<aceIndex | 0xF<<4
+
+// rankMask returns a bitmask of the ranks of the cards in which each bit
+// is the index of a rank in allRanks.
+func rankMask(cards []*Card) uint16 {
+	m := uint16(0)
+	for _, c := range cards {
+		m |= 1 << uint(c.Rank().indexOf())
+	}
+	return m
+}
+
+// hasRun returns true if the mask has five consecutive bits set.
+func hasRun(m uint32) bool {
+	return m&(m>>1)&(m>>2)&(m>>3)&(m>>4) != 0
 }
 
 func formLowStraight(cards []*Card) []*Card {
@@ -515,6 +1488,58 @@ func formLowStraight(cards []*Card) []*Card {
 	return cards
 }
 
+func formShortDeckLowStraight(cards []*Card) []*Card {
+	has := cards[0].Rank() == Ace &&
+		cards[1].Rank() == Nine &&
+		cards[2].Rank() == Eight &&
+		cards[3].Rank() == Seven &&
+		cards[4].Rank() == Six
+	if has {
+		cards = []*Card{cards[1], cards[2], cards[3], cards[4], cards[0]}
+	}
+	return cards
+}
+
+// hasWrapStraight returns true if each card is one rank below the last
+// with the ace following the two, such as 3-2-A-K-Q.
+func hasWrapStraight(cards []*Card) bool {
+	n := len(allRanks())
+	for i := 1; i < 5; i++ {
+		if cards[i].Rank().indexOf() != (cards[i-1].Rank().indexOf()+n-1)%n {
+			return false
+		}
+	}
+	return true
+}
+
+// formWrapStraight rotates cards sorted ace high such as A-K-Q-3-2 so
+// that a straight wrapping around the ace starts with its top card.
+func formWrapStraight(cards []*Card) []*Card {
+	if hasBlankCards(cards) {
+		return cards
+	}
+	for i := range cards {
+		rotated := append(append([]*Card{}, cards[i:]...), cards[:i]...)
+		if hasWrapStraight(rotated) {
+			return rotated
+		}
+	}
+	return cards
+}
+
+// duplicateCard returns the first card whose rank and suit appear earlier
+// in cards or nil if every card is unique.
+func duplicateCard(cards []*Card) *Card {
+	for i, c := range cards {
+		for _, prev := range cards[:i] {
+			if c.Equal(prev) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
 func hasBlankCards(cards []*Card) bool {
 	for _, c := range cards {
 		if strings.Contains(string(c.Rank()), "?") {
@@ -524,6 +1549,27 @@ func hasBlankCards(cards []*Card) bool {
 	return false
 }
 
+// countRank returns the number of cards of the rank.
+func countRank(cards []*Card, r Rank) int {
+	n := 0
+	for _, c := range cards {
+		if c.Rank() == r {
+			n++
+		}
+	}
+	return n
+}
+
+var (
+	// ranks sorted starting w/ ace
+	aceHighRanksDesc = []Rank{Ace, King, Queen, Jack, Ten, Nine, Eight,
+		Seven, Six, Five, Four, Three, Two}
+
+	// ranks sorted starting w/ king
+	aceLowRanksDesc = []Rank{King, Queen, Jack, Ten, Nine, Eight, Seven,
+		Six, Five, Four, Three, Two, Ace}
+)
+
 func cardsForRank(cards []*Card, r Rank) []*Card {
 	rCards := []*Card{}
 	for _, c := range cards {
@@ -0,0 +1,201 @@
+package hand
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/notnil/joker/util"
+)
+
+func TestMarshalJSONEscaping(t *testing.T) {
+	h := New([]*Card{AceSpades, KingSpades, QueenSpades, JackSpades, TenSpades})
+	h.description = `royal "flush" \ with escapes`
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["description"] != h.description {
+		t.Fatalf("description = %q; want %q", m["description"], h.description)
+	}
+	if m["ranking"] != float64(RoyalFlush) {
+		t.Fatalf("ranking = %v; want %d", m["ranking"], RoyalFlush)
+	}
+	const expected = `{"ranking":10,"cards":["A♠","K♠","Q♠","J♠","T♠"],"description":"royal \"flush\" \\ with escapes"}`
+	if string(b) != expected {
+		t.Fatalf("json.Marshal() = %s; want %s", b, expected)
+	}
+}
+
+func TestAceIsLowStraightDescriptions(t *testing.T) {
+	// aceIsLow w/o ignoring straights so the wheel is still a straight
+	aceIsLow := func(c *Config) { c.aceIsLow = true }
+	wheel := []*Card{AceHearts, TwoDiamonds, ThreeClubs, FourSpades, FiveHearts}
+	for _, options := range [][]func(*Config){nil, {aceIsLow}} {
+		h := New(wheel, options...)
+		if h.Ranking() != Straight || h.Description() != "straight five high" {
+			t.Fatalf("expected straight five high got %v", h)
+		}
+		if h.Cards()[0] != FiveHearts {
+			t.Fatalf("expected the five to be the high card got %v", h.Cards())
+		}
+	}
+
+	broadway := []*Card{AceHearts, KingDiamonds, QueenClubs, JackSpades, TenHearts}
+	if h := New(broadway); h.Description() != "straight ace high" || h.Cards()[0] != AceHearts {
+		t.Fatalf("expected straight ace high got %v", h)
+	}
+
+	if h := New(wheel, AceToFiveLow); h.Description() != "five low" {
+		t.Fatalf("expected five low got %v", h)
+	}
+}
+
+// sortedHasStraight is the previous implementation of hasStraight which
+// requires the cards to be arranged by formCards.
+func sortedHasStraight(cards []*Card, c Config) bool {
+	if hasBlankCards(cards) {
+		return false
+	}
+	ranks := func(rs ...Rank) bool {
+		for i, r := range rs {
+			if cards[i].Rank() != r {
+				return false
+			}
+		}
+		return true
+	}
+	lastIndex := cards[0].Rank().indexOf()
+	straight := true
+	for i := 1; i < 5; i++ {
+		index := cards[i].Rank().indexOf()
+		straight = straight && (lastIndex == index+1)
+		lastIndex = index
+	}
+	return straight || (!c.ignoreLowStraight && ranks(Five, Four, Three, Two, Ace)) ||
+		(c.shortDeck && !c.ignoreLowStraight && ranks(Nine, Eight, Seven, Six, Ace)) ||
+		(c.aroundTheCorner && hasWrapStraight(cards))
+}
+
+func TestHasStraightParity(t *testing.T) {
+	configs := []Config{
+		{},
+		{ignoreLowStraight: true},
+		{shortDeck: true},
+		{shortDeck: true, ignoreLowStraight: true},
+		{aroundTheCorner: true},
+		{aroundTheCorner: true, ignoreLowStraight: true},
+		{aceIsLow: true},
+		{aceIsLow: true, aroundTheCorner: true},
+		{sorting: SortingLow},
+	}
+	suits := allSuits()
+	for _, c := range configs {
+		straights := 0
+		for _, combo := range combinations(len(allRanks()), 5) {
+			for _, paired := range []bool{false, true} {
+				cards := []*Card{}
+				for i, j := range combo {
+					cards = append(cards, &Card{rank: allRanks()[j], suit: suits[i%4]})
+				}
+				if paired {
+					cards[1] = &Card{rank: cards[0].rank, suit: suits[3]}
+				}
+				formed := formCards(append([]*Card{}, cards...), c)
+				expected := sortedHasStraight(formed, c)
+				if hasStraight(cards, c) != expected || hasStraight(formed, c) != expected {
+					t.Fatalf("hasStraight(%v, %+v) = %v; want %v", cards, c, !expected, expected)
+				}
+				if expected {
+					straights++
+				}
+			}
+		}
+		if straights == 0 {
+			t.Fatalf("no straights found with %+v", c)
+		}
+	}
+
+	// every straight is found regardless of the order of its cards
+	if n := countStraights(Config{}); n != 10 {
+		t.Fatalf("found %d straights; want 10", n)
+	}
+	if n := countStraights(Config{aroundTheCorner: true}); n != 13 {
+		t.Fatalf("found %d straights around the corner; want 13", n)
+	}
+}
+
+func countStraights(c Config) int {
+	n := 0
+	for _, combo := range combinations(len(allRanks()), 5) {
+		cards := []*Card{}
+		for i := len(combo) - 1; i >= 0; i-- {
+			cards = append(cards, &Card{rank: allRanks()[combo[i]], suit: allSuits()[i%4]})
+		}
+		if hasStraight(cards, c) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestHasFlush(t *testing.T) {
+	// five cards are a flush only if they all share a suit
+	ranks := []Rank{Ace, Jack, Eight, Five, Two}
+	for i := 0; i < 1024; i++ {
+		cards := []*Card{}
+		same := true
+		for j, r := range ranks {
+			s := allSuits()[i>>(2*uint(j))&3]
+			cards = append(cards, &Card{rank: r, suit: s})
+			same = same && s == cards[0].Suit()
+		}
+		if hasFlush(cards) != same {
+			t.Fatalf("hasFlush(%v) = %v; want %v", cards, !same, same)
+		}
+	}
+
+	// the order of the cards doesn't matter
+	tests := map[string]bool{
+		"2h As 3s Kd 9s Ts 4s": true,
+		"2h As 3s Kd 9s Th 4s": false,
+		"Ac 2d 3c 4c 5h 6c 7c": true,
+	}
+	for s, expected := range tests {
+		cards, err := ParseCards(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hasFlush(cards) != expected {
+			t.Fatalf("hasFlush(%v) = %v; want %v", cards, !expected, expected)
+		}
+	}
+
+	// blank cards don't count towards a flush
+	formed := formCards([]*Card{AceSpades, KingSpades, QueenSpades}, Config{})
+	if hasFlush(formed) || !hasShortFlush(formed, Config{flushSize: 3}) {
+		t.Fatalf("expected only a short flush in %v", formed)
+	}
+	if hasShortFlush(formed, Config{flushSize: 4}) || hasShortFlush(formed, Config{}) {
+		t.Fatalf("expected no short flush of four in %v", formed)
+	}
+}
+
+func TestForEachCombination(t *testing.T) {
+	for _, nk := range [][2]int{{7, 2}, {45, 2}, {10, 5}, {5, 5}, {3, 4}, {0, 0}} {
+		n, k := nk[0], nk[1]
+		combos := [][]int{}
+		forEachCombination(n, k, func(combo []int) {
+			combos = append(combos, append([]int{}, combo...))
+		})
+		expected := util.Combinations(n, k)
+		if fmt.Sprint(combos) != fmt.Sprint(expected) {
+			t.Fatalf("forEachCombination(%d, %d) = %v; want %v", n, k, combos, expected)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package hand_test
 
 import (
 	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
 	"testing"
 
 	. "github.com/notnil/joker/hand"
@@ -94,6 +98,18 @@ var tests = []testPair{
 		FourOfAKind,
 		"four of a kind twos",
 	},
+	{
+		jokertest.Cards("Qs", "Qh", "Qd", "Ks", "Kh", "Kd", "2c"),
+		jokertest.Cards("Ks", "Kh", "Kd", "Qs", "Qh"),
+		FullHouse,
+		"full house kings full of queens",
+	},
+	{
+		jokertest.Cards("2s", "2h", "2d", "As", "Ah", "Ad", "3c"),
+		jokertest.Cards("As", "Ah", "Ad", "2s", "2h"),
+		FullHouse,
+		"full house aces full of twos",
+	},
 }
 
 func TestHands(t *testing.T) {
@@ -114,6 +130,82 @@ func TestHands(t *testing.T) {
 	}
 }
 
+var straightTests = []testPair{
+	{
+		jokertest.Cards("Th", "Js", "Qs", "Ks", "As"),
+		jokertest.Cards("As", "Ks", "Qs", "Js", "Th"),
+		Straight,
+		"straight ace high",
+	},
+	{
+		jokertest.Cards("Ts", "Js", "Qs", "Ks", "As", "5s", "4s"),
+		jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"),
+		RoyalFlush,
+		"royal flush",
+	},
+	{
+		jokertest.Cards("Ad", "Kh", "Qh", "Jh", "Th", "9h"),
+		jokertest.Cards("Kh", "Qh", "Jh", "Th", "9h"),
+		StraightFlush,
+		"straight flush king high",
+	},
+	{
+		jokertest.Cards("Ah", "2d", "3c", "4s", "5h", "Kd", "Qc"),
+		jokertest.Cards("5h", "4s", "3c", "2d", "Ah"),
+		Straight,
+		"straight five high",
+	},
+	{
+		jokertest.Cards("Ah", "2h", "3h", "4h", "5h", "Kh"),
+		jokertest.Cards("5h", "4h", "3h", "2h", "Ah"),
+		StraightFlush,
+		"straight flush five high",
+	},
+	{
+		jokertest.Cards("Ah", "2h", "3h", "4h", "5h", "6d"),
+		jokertest.Cards("5h", "4h", "3h", "2h", "Ah"),
+		StraightFlush,
+		"straight flush five high",
+	},
+	{
+		jokertest.Cards("Ah", "2h", "3h", "4h", "5d", "6h"),
+		jokertest.Cards("Ah", "6h", "4h", "3h", "2h"),
+		Flush,
+		"flush ace high",
+	},
+	{
+		jokertest.Cards("Kh", "Ad", "2c", "3s", "4h"),
+		jokertest.Cards("Ad", "Kh", "4h", "3s", "2c"),
+		HighCard,
+		"high card ace high",
+	},
+}
+
+func TestStraights(t *testing.T) {
+	for _, test := range straightTests {
+		h := New(test.cards)
+		if h.Ranking() != test.ranking {
+			t.Fatalf("New(%v) = %v; want %v", test.cards, h, test.ranking)
+		}
+		for i := 0; i < 5; i++ {
+			actual, expected := h.Cards()[i], test.arrangement[i]
+			if !actual.Equal(expected) {
+				t.Fatalf("expected %v got %v", test.arrangement, h.Cards())
+			}
+		}
+		if test.description != h.Description() {
+			t.Fatalf("expected \"%v\" got \"%v\"", test.description, h.Description())
+		}
+	}
+
+	broadway := New(jokertest.Cards("Ah", "Kd", "Qc", "Js", "Th"))
+	wheel := New(jokertest.Cards("Ah", "2d", "3c", "4s", "5h"))
+	six := New(jokertest.Cards("6h", "2d", "3c", "4s", "5h"))
+	if !broadway.Beats(six) || !six.Beats(wheel) {
+		t.Fatalf("expected %v to beat %v to beat %v", broadway, six, wheel)
+	}
+}
+
 type equality int
 
 const (
@@ -139,6 +231,11 @@ var equalityTests = []testEquality{
 		jokertest.Cards("Ts", "9h", "8d", "7c", "6s", "Ah", "Ks"),
 		equalTo,
 	},
+	{
+		jokertest.Cards("Ah", "Kh", "Qd", "Jc", "9s"),
+		jokertest.Cards("Ah", "Kh", "Qd", "Jc", "Ts"),
+		lessThan,
+	},
 }
 
 func TestCompareHands(t *testing.T) {
@@ -164,6 +261,197 @@ func TestCompareHands(t *testing.T) {
 	}
 }
 
+func TestHandPredicates(t *testing.T) {
+	for _, test := range equalityTests {
+		h1 := New(test.cards1)
+		h2 := New(test.cards2)
+		beats, ties, losesTo := h1.Beats(h2), h1.Ties(h2), h1.LosesTo(h2)
+		if beats != (test.e == greaterThan) {
+			t.Errorf("%v Beats(%v) = %v", h1, h2, beats)
+		}
+		if ties != (test.e == equalTo) {
+			t.Errorf("%v Ties(%v) = %v", h1, h2, ties)
+		}
+		if losesTo != (test.e == lessThan) {
+			t.Errorf("%v LosesTo(%v) = %v", h1, h2, losesTo)
+		}
+	}
+}
+
+func TestWinners(t *testing.T) {
+	h1 := New(jokertest.Cards("Ts", "9h", "8d", "7c", "6s"))
+	h2 := New(jokertest.Cards("Kh", "Kd", "2c", "3c", "4s"))
+	h3 := New(jokertest.Cards("Td", "9c", "8h", "7d", "6h"))
+	winners := Winners([]*Hand{h1, h2, h3})
+	if len(winners) != 2 || winners[0] != h1 || winners[1] != h3 {
+		t.Fatalf("Winners() = %v; want %v", winners, []*Hand{h1, h3})
+	}
+
+	indices := WinnerIndices([]*Hand{h2, h1})
+	if len(indices) != 1 || indices[0] != 1 {
+		t.Fatalf("WinnerIndices() = %v; want %v", indices, []int{1})
+	}
+
+	l1 := New(jokertest.Cards("7h", "5d", "4s", "3s", "2h"), DeuceToSevenLow)
+	l2 := New(jokertest.Cards("8h", "5d", "4s", "3s", "2h"), DeuceToSevenLow)
+	if winners := Winners([]*Hand{l2, l1}); len(winners) != 1 || winners[0] != l1 {
+		t.Fatalf("Winners() = %v; want %v", winners, []*Hand{l1})
+	}
+
+	if winners := Winners(nil); len(winners) != 0 {
+		t.Fatalf("Winners(nil) = %v; want none", winners)
+	}
+}
+
+func TestMarginDescription(t *testing.T) {
+	tests := []struct {
+		h, o     string
+		expected string
+	}{
+		{"As Ad Kc 7h 2s", "Ks Kd Ac 7d 2d", "wins with a higher pair"},
+		{"As Ad Kc 7h 2s", "Ah Ac Qc 7d 2d", "wins with a better kicker"},
+		{"As Ad Kc Kh 2s", "Ah Ac Qc Qd 2d", "wins with a higher second pair"},
+		{"As Ad Kc Kh 2s", "Ah Ac Kd Ks 3d", "wins with a better kicker"},
+		{"9s 9d 9c Kh Ks", "9h Ts Tc Th 9c", "wins with a higher three of a kind"},
+		{"Ts Td Tc Kh Ks", "Th Tc Ts Qh Qc", "wins with a higher pair"},
+		{"Ks Qs 9s 7s 2s", "Kh Qh 9h 7h 3h", "wins with a higher flush"},
+		{"As Kd Qc Jh 9s", "Ah Kc Qd Jd 8h", "wins with a better kicker"},
+		{"Ks Kd 7c 5h 2s", "As Kc Qd Jh 9h", "wins with a better hand, pair over high card"},
+		{"As Kd Qc Jh 9s", "Ah Kc Qd Jd 9h", "split pot"},
+	}
+	for _, test := range tests {
+		h, err := ParseHand(test.h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o, err := ParseHand(test.o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := MarginDescription(h, o); d != test.expected {
+			t.Fatalf("MarginDescription(%v, %v) = %q; want %q", h, o, d, test.expected)
+		}
+		if d := MarginDescription(o, h); d != test.expected {
+			t.Fatalf("MarginDescription(%v, %v) = %q; want %q", o, h, d, test.expected)
+		}
+	}
+}
+
+func TestCompareToSuited(t *testing.T) {
+	spades := New(jokertest.Cards("As", "Ks", "Qs", "Js", "9s"))
+	hearts := New(jokertest.Cards("Ah", "Kh", "Qh", "Jh", "9h"))
+	clubs := New(jokertest.Cards("Ac", "Kc", "Qc", "Jc", "9c"))
+	if spades.CompareTo(hearts) != 0 {
+		t.Fatalf("expected %v to tie %v", spades, hearts)
+	}
+	if spades.CompareToSuited(hearts) <= 0 || clubs.CompareToSuited(hearts) >= 0 {
+		t.Fatalf("expected spades > hearts > clubs")
+	}
+	if spades.CompareToSuited(New(jokertest.Cards("As", "Ks", "Qs", "Js", "9s"))) != 0 {
+		t.Fatalf("expected %v to tie itself", spades)
+	}
+
+	if h1, h2 := New(jokertest.Cards("Ah", "Ac", "Kd")), New(jokertest.Cards("Kd", "Ac", "Ah")); h1.CompareToSuited(h2) != 0 {
+		t.Fatalf("expected %v to tie %v regardless of card order", h1, h2)
+	}
+
+	// ranks are compared before suits
+	pair := New(jokertest.Cards("Ac", "Ad", "2c", "3c", "4c"))
+	if pair.CompareToSuited(spades) >= 0 {
+		t.Fatalf("expected %v to lose to %v", pair, spades)
+	}
+
+	// the first differing card decides
+	h1 := New(jokertest.Cards("Ah", "Ac", "Kd", "Qs", "9h"))
+	h2 := New(jokertest.Cards("Ah", "Ac", "Ks", "Qc", "9c"))
+	if h1.CompareToSuited(h2) >= 0 || h2.CompareToSuited(h1) <= 0 {
+		t.Fatalf("expected %v to lose to %v", h1, h2)
+	}
+}
+
+func TestHandKey(t *testing.T) {
+	h1 := New(jokertest.Cards("Ks", "Kh", "7d", "7c", "Qs"))
+	h2 := New(jokertest.Cards("Qh", "7s", "Kc", "7h", "Kd"))
+	if h1.Key() != h2.Key() {
+		t.Fatalf("expected %v and %v to have the same key", h1, h2)
+	}
+
+	r := rand.New(rand.NewSource(9))
+	for i := 0; i < 1000; i++ {
+		deck := NewDeck()
+		deck.Shuffle(r.Shuffle)
+		h1, h2 := New(deck.PopMulti(7)), New(deck.PopMulti(7))
+		if (h1.Key() == h2.Key()) != h1.Ties(h2) {
+			t.Fatalf("%v Key() = %x, %v Key() = %x; Ties = %v", h1, h1.Key(), h2, h2.Key(), h1.Ties(h2))
+		}
+	}
+
+	if New(jokertest.Cards("As", "Ad")).Key() == New(jokertest.Cards("As", "Ad", "2c")).Key() {
+		t.Fatal("expected partial hands of different cards to have different keys")
+	}
+}
+
+func TestSortHands(t *testing.T) {
+	pair := New(jokertest.Cards("Kh", "Kd", "2c", "3c", "4s"))
+	straight := New(jokertest.Cards("Ts", "9h", "8d", "7c", "6s"))
+	high := New(jokertest.Cards("Ah", "Jd", "9c", "7c", "2s"))
+	hands := []*Hand{pair, straight, high}
+
+	SortHands(hands, SortingHigh)
+	if hands[0] != straight || hands[1] != pair || hands[2] != high {
+		t.Fatalf("SortHands(SortingHigh) = %v", hands)
+	}
+	SortHands(hands, SortingLow)
+	if hands[0] != high || hands[1] != pair || hands[2] != straight {
+		t.Fatalf("SortHands(SortingLow) = %v", hands)
+	}
+}
+
+func TestByLowHand(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	high, low := []*Hand{}, []*Hand{}
+	for len(high) < 20 {
+		deck := NewDeck()
+		deck.Shuffle(r.Shuffle)
+		h := New(deck.PopMulti(7))
+		// ties would make the orders ambiguous
+		unique := true
+		for _, o := range high {
+			unique = unique && !h.Ties(o)
+		}
+		if unique {
+			high, low = append(high, h), append(low, h)
+		}
+	}
+	sort.Sort(ByHighHand(high))
+	sort.Sort(ByLowHand(low))
+	for i := range high {
+		if high[i] != low[len(low)-1-i] {
+			t.Fatalf("ByLowHand() = %v; want the reverse of %v", low, high)
+		}
+	}
+}
+
+func TestCompareDetail(t *testing.T) {
+	tests := []struct {
+		cards1, cards2 []*Card
+		index          int
+	}{
+		{jokertest.Cards("Ah", "Ad", "Kc", "Qs", "9h"), jokertest.Cards("Ah", "Ad", "Kc", "Qs", "8h"), 4},
+		{jokertest.Cards("Ah", "Ad", "Kc", "Qs", "9h"), jokertest.Cards("Kh", "Kd", "Ac", "Qs", "9h"), 0},
+		{jokertest.Cards("Ah", "Ad", "Kc", "Qs", "9h"), jokertest.Cards("Ah", "Ad", "Qc", "Js", "9h"), 2},
+		{jokertest.Cards("Ah", "Ad", "Kc", "Qs", "9h"), jokertest.Cards("2h", "2d", "3c", "3s", "9h"), -1},
+		{jokertest.Cards("Ah", "Ad", "Kc", "Qs", "9h"), jokertest.Cards("As", "Ac", "Kd", "Qh", "9c"), -1},
+	}
+	for _, test := range tests {
+		h1, h2 := New(test.cards1), New(test.cards2)
+		cmp, index := h1.CompareDetail(h2)
+		if cmp != h1.CompareTo(h2) || index != test.index {
+			t.Fatalf("%v CompareDetail(%v) = %d, %d; want %d, %d", h1, h2, cmp, index, h1.CompareTo(h2), test.index)
+		}
+	}
+}
+
 type testOptionsPairs struct {
 	cards       []*Card
 	arrangement []*Card
@@ -185,14 +473,28 @@ var optTests = []testOptionsPairs{
 		jokertest.Cards("6h", "5s", "4s", "3s", "2s"),
 		[]func(*Config){AceToFiveLow},
 		HighCard,
-		"high card six high",
+		"six low",
 	},
 	{
 		jokertest.Cards("Ah", "6h", "5s", "4s", "2s", "Ks"),
 		jokertest.Cards("6h", "5s", "4s", "2s", "Ah"),
 		[]func(*Config){AceToFiveLow},
 		HighCard,
-		"high card six high",
+		"six low",
+	},
+	{
+		jokertest.Cards("Ah", "2h", "3s", "4s", "5d"),
+		jokertest.Cards("Ah", "5d", "4s", "3s", "2h"),
+		[]func(*Config){DeuceToSevenLow},
+		HighCard,
+		"high card ace high",
+	},
+	{
+		jokertest.Cards("7h", "5d", "4s", "3s", "2h", "6c", "8c"),
+		jokertest.Cards("7h", "5d", "4s", "3s", "2h"),
+		[]func(*Config){DeuceToSevenLow},
+		HighCard,
+		"high card seven high",
 	},
 }
 
@@ -214,7 +516,265 @@ func TestHandsWithOptions(t *testing.T) {
 	}
 }
 
+func TestDeuceToSevenLow(t *testing.T) {
+	nut := New(jokertest.Cards("7h", "5d", "4s", "3s", "2h"), DeuceToSevenLow)
+	others := []*Hand{
+		New(jokertest.Cards("8h", "6d", "4s", "3s", "2h"), DeuceToSevenLow),
+		New(jokertest.Cards("7h", "6d", "5s", "4s", "3h"), DeuceToSevenLow),
+		New(jokertest.Cards("7h", "5h", "4h", "3h", "2h"), DeuceToSevenLow),
+		New(jokertest.Cards("Ah", "5d", "4s", "3s", "2h"), DeuceToSevenLow),
+		New(jokertest.Cards("2c", "5d", "4s", "3s", "2h"), DeuceToSevenLow),
+	}
+	for _, o := range others {
+		hands := Sort(SortingLow, DESC, o, nut)
+		if hands[0] != nut {
+			t.Errorf("expected %v to be a better low than %v", nut, o)
+		}
+	}
+}
+
+func TestAceToFiveLow(t *testing.T) {
+	wheel := New(jokertest.Cards("5h", "4d", "3s", "2s", "Ah"), AceToFiveLow)
+	if wheel.Description() != "five low" {
+		t.Fatalf("expected \"five low\" got \"%v\"", wheel.Description())
+	}
+	others := []*Hand{
+		New(jokertest.Cards("6h", "4d", "3s", "2s", "Ah"), AceToFiveLow),
+		New(jokertest.Cards("6h", "5d", "4s", "3s", "2h"), AceToFiveLow),
+		New(jokertest.Cards("5h", "5d", "3s", "2s", "Ah"), AceToFiveLow),
+	}
+	for _, o := range others {
+		hands := Sort(SortingLow, DESC, o, wheel)
+		if hands[0] != wheel {
+			t.Errorf("expected %v to be a better low than %v", wheel, o)
+		}
+	}
+
+	h1 := New(jokertest.Cards("6h", "5d", "4s", "3s", "Ah"), AceToFiveLow)
+	h2 := New(jokertest.Cards("6h", "5d", "4s", "3s", "2h"), AceToFiveLow)
+	if h1.CompareTo(h2) >= 0 {
+		t.Errorf("expected %v to be less than %v", h1, h2)
+	}
+}
+
+func TestNoWheel(t *testing.T) {
+	wheel := jokertest.Cards("Ah", "2d", "3c", "4s", "5h")
+	if h := New(wheel); h.Ranking() != Straight {
+		t.Fatalf("New(%v) = %v; want %v", wheel, h, Straight)
+	}
+	if h := New(wheel, NoWheel); h.Ranking() != HighCard || h.Description() != "high card ace high" {
+		t.Fatalf("New(%v, NoWheel) = %v; want high card ace high", wheel, h)
+	}
+
+	wheelFlush := jokertest.Cards("Ah", "2h", "3h", "4h", "5h")
+	if h := New(wheelFlush, NoWheel); h.Ranking() != Flush {
+		t.Fatalf("New(%v, NoWheel) = %v; want %v", wheelFlush, h, Flush)
+	}
+
+	broadway := jokertest.Cards("Ah", "Kd", "Qc", "Js", "Th", "2d", "3c")
+	if h := New(broadway, NoWheel); h.Ranking() != Straight {
+		t.Fatalf("New(%v, NoWheel) = %v; want %v", broadway, h, Straight)
+	}
+	six := New(jokertest.Cards("6h", "2d", "3c", "4s", "5h"), NoWheel)
+	if six.Ranking() != Straight || !six.Beats(New(wheel, NoWheel)) {
+		t.Fatalf("expected %v to beat %v", six, New(wheel, NoWheel))
+	}
+}
+
+func TestAroundTheCorner(t *testing.T) {
+	tests := []struct {
+		cards       []*Card
+		description string
+	}{
+		{jokertest.Cards("Kh", "Ad", "2c", "3s", "4h"), "straight four high"},
+		{jokertest.Cards("Qh", "Kd", "Ac", "2s", "3h", "3d"), "straight three high"},
+		{jokertest.Cards("Jh", "Qd", "Kc", "As", "2h"), "straight two high"},
+		{jokertest.Cards("Ah", "2d", "3c", "4s", "5h"), "straight five high"},
+		{jokertest.Cards("Ah", "Kd", "Qc", "Js", "Th"), "straight ace high"},
+		{jokertest.Cards("Kh", "Ah", "2h", "3h", "4h"), "straight flush four high"},
+	}
+	for _, test := range tests {
+		h := New(test.cards, AroundTheCorner)
+		if h.Description() != test.description {
+			t.Fatalf("New(%v, AroundTheCorner) = %v; want %v", test.cards, h, test.description)
+		}
+	}
+
+	wrap := jokertest.Cards("Kh", "Ad", "2c", "3s", "4h")
+	if h := New(wrap); h.Ranking() != HighCard {
+		t.Fatalf("New(%v) = %v; want %v", wrap, h, HighCard)
+	}
+	if h := New(jokertest.Cards("Kh", "Ad", "2c", "3s", "5h"), AroundTheCorner); h.Ranking() != HighCard {
+		t.Fatalf("expected %v to not be a straight", h)
+	}
+	h := New(wrap, AroundTheCorner)
+	wheel := New(jokertest.Cards("Ah", "2d", "3c", "4s", "5h"), AroundTheCorner)
+	trips := New(jokertest.Cards("Ah", "Ad", "Ac", "4s", "5h"), AroundTheCorner)
+	if !h.LosesTo(wheel) || !h.Beats(trips) {
+		t.Fatalf("expected %v to lose to %v and beat %v", h, wheel, trips)
+	}
+}
+
+func TestFlushSize(t *testing.T) {
+	tests := []struct {
+		cards       []*Card
+		size        int
+		description string
+	}{
+		{jokertest.Cards("Ah", "Kd", "9d", "5d", "2d"), 4, "flush king high"},
+		{jokertest.Cards("Ah", "Kd", "9d", "5d", "2d"), 5, "high card ace high"},
+		{jokertest.Cards("Ad", "Ah", "9d", "5d", "2d"), 4, "flush ace high"},
+		{jokertest.Cards("Ad", "Ah", "9d", "5d", "2c"), 4, "pair of aces"},
+		{jokertest.Cards("9h", "8d", "7d", "6d", "5d"), 4, "flush eight high"},
+		{jokertest.Cards("9d", "8d", "7d", "6d", "5d"), 4, "straight flush nine high"},
+		{jokertest.Cards("Ad", "Ah", "Ac", "5d", "2d"), 3, "flush ace high"},
+		{jokertest.Cards("7h", "7d", "7c", "2d", "2h"), 3, "full house sevens full of twos"},
+		{jokertest.Cards("Ks", "Qs", "Js", "9s", "9h"), 4, "flush king high"},
+		{jokertest.Cards("9s", "9h", "9d", "Ks", "Qs"), 3, "flush king high"},
+	}
+	for _, test := range tests {
+		h := New(test.cards, FlushSize(test.size))
+		if h.Description() != test.description {
+			t.Fatalf("New(%v, FlushSize(%d)) = %v; want %v", test.cards, test.size, h, test.description)
+		}
+	}
+
+	// the flush cards come first even if the kickers are paired
+	paired := New(jokertest.Cards("Ks", "Qs", "Js", "9s", "9h"), FlushSize(4))
+	if s := fmt.Sprint(paired.Cards()); s != "[K♠ Q♠ J♠ 9♠ 9♥]" {
+		t.Fatalf("Cards() = %v; want [K♠ Q♠ J♠ 9♠ 9♥]", s)
+	}
+	lower := New(jokertest.Cards("Ks", "Qs", "Js", "8s", "2h"), FlushSize(4))
+	if !paired.Beats(lower) {
+		t.Fatalf("expected %v to beat %v", paired, lower)
+	}
+	trips := New(jokertest.Cards("9s", "9h", "9d", "Ks", "Qs"), FlushSize(3))
+	if s := fmt.Sprint(trips.Cards()); s != "[K♠ Q♠ 9♠ 9♥ 9♦]" {
+		t.Fatalf("Cards() = %v; want [K♠ Q♠ 9♠ 9♥ 9♦]", s)
+	}
+	if !trips.Beats(New(jokertest.Cards("Qs", "Js", "Ts", "Ah", "Ad"), FlushSize(3))) {
+		t.Fatalf("expected %v to beat a queen high flush", trips)
+	}
+
+	fourFlush := New(jokertest.Cards("Ah", "Kd", "9d", "5d", "2d"), FlushSize(4))
+	straight := New(jokertest.Cards("Ah", "Kd", "Qd", "Js", "Tc"), FlushSize(4))
+	if !fourFlush.Beats(straight) {
+		t.Fatalf("expected %v to beat %v", fourFlush, straight)
+	}
+	for _, n := range []int{2, 6} {
+		if _, err := NewErr(jokertest.Cards("Ah", "Kd", "9d", "5d", "2d"), FlushSize(n)); err == nil {
+			t.Fatalf("NewErr() should return an error for FlushSize(%d)", n)
+		}
+	}
+}
+
+func TestShortDeckLowStraight(t *testing.T) {
+	low := New(jokertest.Cards("Ah", "6d", "7c", "8s", "9h", "Kd", "Kc"), ShortDeck)
+	if low.Ranking() != Straight || low.Description() != "straight nine high" {
+		t.Fatalf("expected straight nine high got %v", low)
+	}
+	if low.Cards()[4] != AceHearts {
+		t.Fatalf("expected the ace to play low in %v", low.Cards())
+	}
+	ten := New(jokertest.Cards("6h", "7d", "8c", "9s", "Th"), ShortDeck)
+	if !low.LosesTo(ten) {
+		t.Fatalf("expected %v to lose to %v", low, ten)
+	}
+	trips := New(jokertest.Cards("Ah", "Ad", "Ac", "8s", "9h"), ShortDeck)
+	if !low.LosesTo(trips) {
+		t.Fatalf("expected %v to lose to %v", low, trips)
+	}
+
+	sf := New(jokertest.Cards("Ah", "6h", "7h", "8h", "9h"), ShortDeck)
+	if sf.Ranking() != StraightFlush || sf.Description() != "straight flush nine high" {
+		t.Fatalf("expected straight flush nine high got %v", sf)
+	}
+	if h := New(jokertest.Cards("Ah", "6d", "7c", "8s", "9h")); h.Ranking() != HighCard {
+		t.Fatalf("expected A-6-7-8-9 to not be a straight w/o ShortDeck got %v", h)
+	}
+}
+
+func TestShortDeck(t *testing.T) {
+	flush := jokertest.Cards("Ks", "Ts", "9s", "7s", "6s")
+	fullHouse := jokertest.Cards("Ah", "Ad", "Ac", "Kd", "Kc")
+	if New(flush).CompareTo(New(fullHouse)) >= 0 {
+		t.Fatal("expected a full house to beat a flush")
+	}
+	if New(flush, ShortDeck).CompareTo(New(fullHouse, ShortDeck)) <= 0 {
+		t.Fatal("expected a flush to beat a full house in short deck")
+	}
+
+	trips := jokertest.Cards("6h", "6d", "6c", "Kd", "Qc")
+	straight := jokertest.Cards("Ah", "Kd", "Qc", "Jd", "Tc")
+	if New(trips, ShortDeck).CompareTo(New(straight, ShortDeck)) <= 0 {
+		t.Fatal("expected three of a kind to beat a straight in short deck")
+	}
+
+	// the flush is selected over the full house
+	h := New(jokertest.Cards("Ks", "Kd", "Kc", "9s", "9h", "7s", "6s", "Ts"), ShortDeck)
+	if h.Ranking() != Flush {
+		t.Fatalf("expected %v got %v", Flush, h.Ranking())
+	}
+
+	if _, err := NewErr(jokertest.Cards("5s", "Ts", "9s", "7s", "6s"), ShortDeck); err == nil {
+		t.Fatal("expected an error for a five in short deck")
+	}
+}
+
+func TestShortDescription(t *testing.T) {
+	expected := []string{"HC AKQJ9", "1P QQKJ9", "2P QQ22J", "3K 666KQ", "ST AKQJT",
+		"ST 5432A", "FL 75432", "FH 77733", "4K 77773", "SF KQJT9", "SF 5432A",
+		"RF AKQJT", "4K 2222A", "FH KKKQQ", "FH AAA22"}
+	for i, test := range tests {
+		h := New(test.cards)
+		if s := h.ShortDescription(); s != expected[i] {
+			t.Fatalf("ShortDescription() = %q; want %q", s, expected[i])
+		}
+	}
+	if s := New(jokertest.Cards("Qh", "Qs")).ShortDescription(); s != "1P QQ" {
+		t.Fatalf("ShortDescription() = %q; want %q", s, "1P QQ")
+	}
+}
+
+func TestKickers(t *testing.T) {
+	tests := []struct {
+		cards   []*Card
+		kickers []*Card
+	}{
+		{jokertest.Cards("Ks", "Qs", "Js", "As", "9d"), jokertest.Cards("Ks", "Qs", "Js", "9d")},
+		{jokertest.Cards("Ks", "Qh", "Qs", "Js", "9d"), jokertest.Cards("Ks", "Js", "9d")},
+		{jokertest.Cards("2s", "Qh", "Qs", "Js", "2d"), jokertest.Cards("Js")},
+		{jokertest.Cards("6s", "Qh", "Ks", "6h", "6d"), jokertest.Cards("Ks", "Qh")},
+		{jokertest.Cards("7s", "7d", "3s", "7c", "7h"), jokertest.Cards("3s")},
+		{jokertest.Cards("7s", "7d", "3s", "3d", "7h"), jokertest.Cards()},
+		{jokertest.Cards("Ks", "Qs", "Js", "As", "Td"), jokertest.Cards()},
+		{jokertest.Cards("7s", "4s", "5s", "3s", "2s"), jokertest.Cards()},
+		{jokertest.Cards("Qh", "Qs"), jokertest.Cards()},
+	}
+	for _, test := range tests {
+		kickers := New(test.cards).Kickers()
+		if len(kickers) != len(test.kickers) {
+			t.Fatalf("Kickers() = %v; want %v", kickers, test.kickers)
+		}
+		for i := range kickers {
+			if kickers[i] != test.kickers[i] {
+				t.Fatalf("Kickers() = %v; want %v", kickers, test.kickers)
+			}
+		}
+	}
+}
+
 func TestBlanks(t *testing.T) {
+	for _, cards := range [][]*Card{nil, {}} {
+		hand := New(cards)
+		if hand.Ranking() != HighCard {
+			t.Fatal("blank card error")
+		}
+		if len(hand.Cards()) != 5 {
+			t.Fatalf("expected 5 cards got %d", len(hand.Cards()))
+		}
+	}
+
 	cards := []*Card{AceSpades}
 	hand := New(cards)
 	if hand.Ranking() != HighCard {
@@ -226,38 +786,1023 @@ func TestBlanks(t *testing.T) {
 	if hand.Ranking() != Pair {
 		t.Fatal("blank card error")
 	}
-}
 
-func TestDeck(t *testing.T) {
-	deck := NewDealer().Deck()
-	if deck.Pop() == deck.Pop() {
-		t.Fatal("Two Pop() calls should never return the same result")
-	}
-	l := len(deck.Cards)
-	if l != 50 {
-		t.Fatalf("After Pop() deck len = %d; want %d", l, 50)
+	cards = []*Card{FiveSpades, KingClubs}
+	hand = New(cards)
+	if hand.Ranking() != HighCard {
+		t.Fatal("blank card error")
 	}
 }
 
-func TestCardJSON(t *testing.T) {
-	card := AceSpades
+func TestNewErr(t *testing.T) {
+	invalid := [][]*Card{
+		{AceSpades, nil, KingSpades},
+		{AceSpades, &Card{}, KingSpades, QueenSpades, JackSpades},
+	}
+	for _, cards := range invalid {
+		if _, err := NewErr(cards); err == nil {
+			t.Fatalf("NewErr(%v) should return an error", cards)
+		}
+	}
 
-	// to json
-	b, err := json.Marshal(card)
+	h, err := NewErr(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"))
 	if err != nil {
 		t.Fatal(err)
 	}
+	if h.Ranking() != RoyalFlush {
+		t.Fatalf("expected %v got %v", RoyalFlush, h.Ranking())
+	}
+}
 
-	// and back
-	cardCopy := KingHearts
-	if err := json.Unmarshal(b, cardCopy); err != nil {
+func TestRejectDuplicates(t *testing.T) {
+	dup := &Card{}
+	if err := dup.UnmarshalText([]byte("A♠")); err != nil {
+		t.Fatal(err)
+	}
+	cards := []*Card{AceSpades, KingSpades, dup, QueenSpades}
+	if _, err := NewErr(cards, RejectDuplicates); err == nil {
+		t.Fatalf("NewErr(%v) should return a duplicate card error", cards)
+	}
+	if _, err := NewErr(cards); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewErr(jokertest.Cards("As", "Ks", "Qs"), RejectDuplicates); err != nil {
 		t.Fatal(err)
 	}
 }
 
-func BenchmarkHandCreation(b *testing.B) {
-	for i := 0; i < b.N; i++ {
-		cards := NewDealer().Deck().PopMulti(7)
+func TestCards(t *testing.T) {
+	cards := Cards()
+	if len(cards) != 52 {
+		t.Fatalf("len(Cards()) = %d; want %d", len(cards), 52)
+	}
+	suits := []Suit{Spades, Hearts, Diamonds, Clubs}
+	ranks := []Rank{Ace, King, Queen, Jack, Ten, Nine, Eight, Seven, Six, Five, Four, Three, Two}
+	for i, c := range cards {
+		suit, rank := suits[i/13], ranks[i%13]
+		if c.Suit() != suit || c.Rank() != rank {
+			t.Fatalf("Cards()[%d] = %v; want %v%v", i, c, rank, suit)
+		}
+	}
+}
+
+func TestCardEqual(t *testing.T) {
+	card := &Card{}
+	if err := card.UnmarshalText([]byte("A♠")); err != nil {
+		t.Fatal(err)
+	}
+	if card == AceSpades || !card.Equal(AceSpades) || !AceSpades.Equal(card) {
+		t.Fatalf("expected %v to equal %v", card, AceSpades)
+	}
+	if card.Equal(AceHearts) || card.Equal(KingSpades) || card.Equal(nil) {
+		t.Fatalf("expected %v to only equal the ace of spades", card)
+	}
+}
+
+func TestNewCard(t *testing.T) {
+	if c := NewCard(Queen, Diamonds); c != QueenDiamonds {
+		t.Fatalf("NewCard() = %v; want %v", c, QueenDiamonds)
+	}
+	for _, c := range Cards() {
+		if n := NewCard(c.Rank(), c.Suit()); n != c {
+			t.Fatalf("NewCard() = %v; want %v", n, c)
+		}
+	}
+	invalid := []struct {
+		r Rank
+		s Suit
+	}{{"1", Spades}, {Ace, "x"}, {"", ""}}
+	for _, test := range invalid {
+		if _, err := NewCardChecked(test.r, test.s); err == nil {
+			t.Fatalf("NewCardChecked(%q, %q) should return an error", test.r, test.s)
+		}
+	}
+}
+
+func TestCardID(t *testing.T) {
+	tests := map[*Card]int{
+		TwoSpades:   0,
+		TwoHearts:   1,
+		TwoClubs:    3,
+		ThreeSpades: 4,
+		TenDiamonds: 34,
+		AceSpades:   48,
+		AceClubs:    51,
+	}
+	for c, id := range tests {
+		if c.ID() != id {
+			t.Fatalf("%v.ID() = %d; want %d", c, c.ID(), id)
+		}
+	}
+	seen := map[int]bool{}
+	for _, c := range Cards() {
+		if CardFromID(c.ID()) != c {
+			t.Fatalf("CardFromID(%d) = %v; want %v", c.ID(), CardFromID(c.ID()), c)
+		}
+		seen[c.ID()] = true
+	}
+	if len(seen) != 52 {
+		t.Fatalf("Cards() have %d distinct ids; want 52", len(seen))
+	}
+	for _, id := range []int{-1, 52} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("CardFromID(%d) should panic", id)
+				}
+			}()
+			CardFromID(id)
+		}()
+	}
+}
+
+func TestCardColor(t *testing.T) {
+	tests := map[*Card]Color{
+		AceSpades:   Black,
+		AceHearts:   Red,
+		AceDiamonds: Red,
+		AceClubs:    Black,
+		TwoDiamonds: Red,
+		QueenClubs:  Black,
+		TenHearts:   Red,
+		EightSpades: Black,
+	}
+	for c, expected := range tests {
+		if c.Color() != expected {
+			t.Fatalf("%v Color() = %v; want %v", c, c.Color(), expected)
+		}
+	}
+}
+
+func TestCanonicalizeSuits(t *testing.T) {
+	tests := map[string]string{
+		"Ah Kh":          "A♠ K♠",
+		"As Ks":          "A♠ K♠",
+		"Ad Kc":          "A♠ K♥",
+		"Qc Jd Qd Tc 9h": "Q♠ J♥ Q♥ T♠ 9♦",
+		"2c 3d 4h 5s 6c": "2♠ 3♥ 4♦ 5♣ 6♠",
+		"":               "",
+	}
+	for s, expected := range tests {
+		cards, err := ParseCards(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		canonical := []string{}
+		for _, c := range CanonicalizeSuits(cards) {
+			canonical = append(canonical, c.String())
+		}
+		if actual := strings.Join(canonical, " "); actual != expected {
+			t.Fatalf("CanonicalizeSuits(%v) = %v; want %v", cards, actual, expected)
+		}
+	}
+}
+
+func TestRemainingCards(t *testing.T) {
+	used := jokertest.Cards("As", "Kh", "2c")
+	used = append(used, &Card{})
+	if err := used[3].UnmarshalText([]byte("T♦")); err != nil {
+		t.Fatal(err)
+	}
+	remaining := RemainingCards(used)
+	if len(remaining) != 48 {
+		t.Fatalf("len(RemainingCards()) = %d; want %d", len(remaining), 48)
+	}
+	for _, c := range remaining {
+		if c == AceSpades || c == KingHearts || c == TwoClubs || c == TenDiamonds {
+			t.Fatalf("RemainingCards() contains used card %v", c)
+		}
+	}
+	if len(RemainingCards(nil)) != 52 {
+		t.Fatal("RemainingCards(nil) should return every card")
+	}
+}
+
+func TestDeck(t *testing.T) {
+	deck := NewDealer().Deck()
+	if deck.Pop() == deck.Pop() {
+		t.Fatal("Two Pop() calls should never return the same result")
+	}
+	l := len(deck.Cards)
+	if l != 50 {
+		t.Fatalf("After Pop() deck len = %d; want %d", l, 50)
+	}
+}
+
+func TestDeckDraw(t *testing.T) {
+	d1, d2 := NewDeck(), NewDeck()
+	d1.Shuffle(SeededShuffle(42))
+	d2.Shuffle(rand.New(rand.NewSource(42)).Shuffle)
+
+	cards1, err := d1.Draw(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards2, err := d2.Draw(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range cards1 {
+		if cards1[i] != cards2[i] {
+			t.Fatalf("Draw() = %v; want %v", cards2, cards1)
+		}
+	}
+
+	if _, err := d1.Draw(46); err != ErrDeckExhausted {
+		t.Fatalf("Draw(46) error = %v; want %v", err, ErrDeckExhausted)
+	}
+	if l := len(d1.Cards); l != 45 {
+		t.Fatalf("after failed Draw() deck len = %d; want %d", l, 45)
+	}
+	if _, err := d1.Draw(45); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeckShuffle(t *testing.T) {
+	// a custom shuffle that reverses the deck
+	deck := NewDeck()
+	deck.Shuffle(func(n int, swap func(i, j int)) {
+		for i := 0; i < n/2; i++ {
+			swap(i, n-1-i)
+		}
+	})
+	if deck.Cards[0] != TwoClubs || deck.Cards[51] != AceSpades {
+		t.Fatalf("Shuffle() = %v; want a reversed deck", deck)
+	}
+
+	d1, d2 := NewDeck(), NewDeck()
+	d1.Shuffle(SeededShuffle(7))
+	d2.Shuffle(SeededShuffle(7))
+	if d1.String() != d2.String() || d1.String() == NewDeck().String() {
+		t.Fatalf("SeededShuffle(7) should shuffle reproducibly got %v and %v", d1, d2)
+	}
+	if len(d1.Cards) != 52 || len(RemainingCards(d1.Cards)) != 0 {
+		t.Fatalf("Shuffle() should keep every card got %v", d1)
+	}
+}
+
+func TestDeckFromSeed(t *testing.T) {
+	if DeckFromSeed("hand 1234").String() != DeckFromSeed("hand 1234").String() {
+		t.Fatal("DeckFromSeed() should deal the same deck for the same seed")
+	}
+	if DeckFromSeed("hand 1234").String() == DeckFromSeed("hand 1235").String() {
+		t.Fatal("DeckFromSeed() should deal different decks for different seeds")
+	}
+
+	// the mapping from seed to order must not change between releases
+	tests := map[string]string{
+		"hand 1234": "[6♥ 6♠ 2♠ 4♣ 8♥]",
+		"":          "[5♠ 3♠ T♣ 4♣ Q♠]",
+	}
+	for seed, expected := range tests {
+		if top := fmt.Sprint(DeckFromSeed(seed).PopMulti(5)); top != expected {
+			t.Fatalf("DeckFromSeed(%q) dealt %v; want %v", seed, top, expected)
+		}
+	}
+}
+
+func TestDeckSecureShuffle(t *testing.T) {
+	const perCard = 200
+	counts := map[*Card]int{}
+	for i := 0; i < 52*perCard; i++ {
+		deck := NewDeck()
+		if err := deck.SecureShuffle(); err != nil {
+			t.Fatal(err)
+		}
+		if len(deck.Cards) != 52 || len(RemainingCards(deck.Cards)) != 0 {
+			t.Fatalf("SecureShuffle() should keep every card got %v", deck)
+		}
+		counts[deck.Cards[0]]++
+	}
+
+	// chi-squared test that each card is equally likely to be first.  The
+	// critical value for 51 degrees of freedom at p = 0.0001 is about 97.
+	chi2 := 0.0
+	for _, c := range Cards() {
+		d := float64(counts[c] - perCard)
+		chi2 += d * d / perCard
+	}
+	if chi2 > 97 {
+		t.Fatalf("first card distribution isn't uniform, chi-squared = %v", chi2)
+	}
+}
+
+func TestDeckDealHands(t *testing.T) {
+	deck := NewDeck()
+	order := NewDeck().PopMulti(6)
+	hands, err := deck.DealHands(3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, hand := range hands {
+		if len(hand) != 2 || hand[0] != order[p] || hand[1] != order[p+3] {
+			t.Fatalf("DealHands() = %v; want round robin deal of %v", hands, order)
+		}
+	}
+	if len(deck.Cards) != 46 {
+		t.Fatalf("after DealHands() deck len = %d; want %d", len(deck.Cards), 46)
+	}
+
+	if _, err := deck.DealHands(10, 5); err != ErrDeckExhausted {
+		t.Fatalf("DealHands(10, 5) error = %v; want %v", err, ErrDeckExhausted)
+	}
+	if len(deck.Cards) != 46 {
+		t.Fatalf("after failed DealHands() deck len = %d; want %d", len(deck.Cards), 46)
+	}
+}
+
+func TestDeckRemove(t *testing.T) {
+	deck := NewDeck()
+	aceHearts := *AceHearts
+	n := deck.Remove(&aceHearts, TwoClubs, AceHearts)
+	if n != 2 || len(deck.Cards) != 50 {
+		t.Fatalf("Remove() = %d leaving %d cards; want %d leaving %d", n, len(deck.Cards), 2, 50)
+	}
+	if n := deck.Remove(TwoClubs); n != 0 {
+		t.Fatalf("Remove() = %d for a removed card; want 0", n)
+	}
+
+	expected := RemainingCards([]*Card{AceHearts, TwoClubs})
+	for i, c := range deck.Cards {
+		if c != expected[i] {
+			t.Fatalf("Remove() changed the deck order to %v", deck)
+		}
+	}
+}
+
+func TestDeckCutAndBurn(t *testing.T) {
+	deck := NewDeck()
+	deck.Cut(2)
+	if len(deck.Cards) != 52 || deck.Cards[0] != ThreeClubs || deck.Cards[1] != TwoClubs {
+		t.Fatalf("Cut(2) left %v at the bottom; want [3♣ 2♣]", deck.Cards[:2])
+	}
+	if deck.Cards[2] != AceSpades || deck.Cards[51] != FourClubs {
+		t.Fatalf("Cut(2) = %v", deck)
+	}
+	deck.Cut(0)
+	deck.Cut(52)
+	if deck.Cards[0] != ThreeClubs || deck.Cards[51] != FourClubs {
+		t.Fatalf("Cut(0) and Cut(52) should not change the deck got %v", deck)
+	}
+
+	c, err := deck.Burn()
+	if err != nil || c != FourClubs || len(deck.Cards) != 51 {
+		t.Fatalf("Burn() = %v, %v leaving %d cards; want %v leaving 51", c, err, len(deck.Cards), FourClubs)
+	}
+	if top := deck.Pop(); top != FiveClubs {
+		t.Fatalf("Pop() after Burn() = %v; want %v", top, FiveClubs)
+	}
+	deck.Burn()
+	if len(deck.Burned) != 2 || deck.Burned[0] != FourClubs || deck.Burned[1] != SixClubs {
+		t.Fatalf("Burned = %v; want [4♣ 6♣]", deck.Burned)
+	}
+
+	deck.Cards = nil
+	if _, err := deck.Burn(); err != ErrDeckExhausted {
+		t.Fatalf("Burn() error = %v; want %v", err, ErrDeckExhausted)
+	}
+}
+
+func TestParseRank(t *testing.T) {
+	tests := map[string]Rank{
+		"K": King, "k": King, "king": King, "KING": King,
+		"T": Ten, "t": Ten, "10": Ten, "Ten": Ten,
+		"2": Two, "two": Two, "A": Ace, "ace": Ace,
+	}
+	for s, expected := range tests {
+		r, err := ParseRank(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r != expected {
+			t.Fatalf("ParseRank(%q) = %v; want %v", s, r, expected)
+		}
+	}
+	for _, s := range []string{"", "1", "kings", "11", "?1"} {
+		if _, err := ParseRank(s); err == nil {
+			t.Fatalf("ParseRank(%q) should return an error", s)
+		}
+	}
+}
+
+func TestParseSuit(t *testing.T) {
+	tests := map[Suit][]string{
+		Spades:   {"s", "S", "spades", "Spades", "♠"},
+		Hearts:   {"h", "H", "hearts", "HEARTS", "♥"},
+		Diamonds: {"d", "D", "diamonds", "Diamonds", "♦"},
+		Clubs:    {"c", "C", "clubs", "Clubs", "♣"},
+	}
+	for expected, strs := range tests {
+		for _, s := range strs {
+			suit, err := ParseSuit(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if suit != expected {
+				t.Fatalf("ParseSuit(%q) = %v; want %v", s, suit, expected)
+			}
+		}
+	}
+	for _, s := range []string{"", "x", "spade", "♠♠"} {
+		if _, err := ParseSuit(s); err == nil {
+			t.Fatalf("ParseSuit(%q) should return an error", s)
+		}
+	}
+}
+
+func TestSuitSymbolAndLetter(t *testing.T) {
+	tests := []struct {
+		suit           Suit
+		symbol, letter string
+	}{
+		{Spades, "♠", "s"},
+		{Hearts, "♥", "h"},
+		{Diamonds, "♦", "d"},
+		{Clubs, "♣", "c"},
+	}
+	for _, test := range tests {
+		if test.suit.Symbol() != test.symbol || test.suit.Letter() != test.letter {
+			t.Fatalf("%v Symbol() = %q, Letter() = %q", test.suit, test.suit.Symbol(), test.suit.Letter())
+		}
+		for _, s := range []string{test.suit.Symbol(), test.suit.Letter()} {
+			if suit, err := ParseSuit(s); err != nil || suit != test.suit {
+				t.Fatalf("ParseSuit(%q) = %v, %v; want %v", s, suit, err, test.suit)
+			}
+		}
+	}
+	if l := Suit("x").Letter(); l != "" {
+		t.Fatalf("Letter() = %q for an invalid suit", l)
+	}
+}
+
+func TestParseCards(t *testing.T) {
+	cards, err := ParseCards("As Kh  T♦ 2♣")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*Card{AceSpades, KingHearts, TenDiamonds, TwoClubs}
+	if len(cards) != len(expected) {
+		t.Fatalf("ParseCards() = %v; want %v", cards, expected)
+	}
+	for i := range cards {
+		if cards[i] != expected[i] {
+			t.Fatalf("ParseCards() = %v; want %v", cards, expected)
+		}
+	}
+
+	for _, s := range []string{"As Kx", "As 1h", "As Khh", "10h"} {
+		if _, err := ParseCards(s); err == nil {
+			t.Fatalf("ParseCards(%q) should return an error", s)
+		}
+	}
+
+	h, err := ParseHand("As Ks Qs Js Ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Ranking() != RoyalFlush {
+		t.Fatalf("expected %v got %v", RoyalFlush, h.Ranking())
+	}
+	if _, err := ParseHand("As Ks Qs Js Tx"); err == nil {
+		t.Fatal("ParseHand() should return an error")
+	}
+}
+
+func TestCardJSON(t *testing.T) {
+	card := AceSpades
+
+	// to json
+	b, err := json.Marshal(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and back
+	cardCopy := &Card{}
+	if err := json.Unmarshal(b, cardCopy); err != nil {
+		t.Fatal(err)
+	}
+	if *cardCopy != *card {
+		t.Fatalf("json.Unmarshal(%s) = %v; want %v", b, cardCopy, card)
+	}
+}
+
+func TestHandClone(t *testing.T) {
+	h := New(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"))
+	clone := h.Clone()
+	if !clone.Ties(h) || clone.Ranking() != h.Ranking() || clone.Description() != h.Description() {
+		t.Fatalf("Clone() = %v; want %v", clone, h)
+	}
+
+	for i := range h.Cards() {
+		if h.Cards()[i] == clone.Cards()[i] {
+			t.Fatalf("Clone() shares card %v", h.Cards()[i])
+		}
+	}
+}
+
+func TestHandCardsCopy(t *testing.T) {
+	h := New(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"))
+	o := New(jokertest.Cards("Ah", "Kh", "Qh", "Jh", "Th"))
+	cards := h.Cards()
+	cards[0], cards[4] = cards[4], cards[0]
+	cards = append(cards[:1], TwoClubs, TwoDiamonds)
+	if h.Ranking() != RoyalFlush || h.Cards()[0] != AceSpades || len(h.Cards()) != 5 {
+		t.Fatalf("modifying Cards() changed the hand %v", h)
+	}
+	if !h.Ties(o) {
+		t.Fatalf("expected %v to be equal to %v", h, o)
+	}
+}
+
+func TestHandIsPartial(t *testing.T) {
+	if h := New(jokertest.Cards("As", "Ah", "Kd")); !h.IsPartial() {
+		t.Fatalf("expected %v to be partial", h)
+	}
+	if h := New(jokertest.Cards("As", "Ah", "Kd", "2c", "3c", "4c")); h.IsPartial() {
+		t.Fatalf("expected %v to not be partial", h)
+	}
+}
+
+func TestHandRanksAndSuits(t *testing.T) {
+	h := New(jokertest.Cards("7d", "Ks", "7c", "Kh", "Qs"))
+	ranks, suits := h.Ranks(), h.Suits()
+	if fmt.Sprint(ranks) != "[K K 7 7 Q]" || fmt.Sprint(suits) != "[♠ ♥ ♦ ♣ ♠]" {
+		t.Fatalf("Ranks() = %v, Suits() = %v for %v", ranks, suits, h)
+	}
+
+	h = New(jokertest.Cards("As", "Ah"))
+	if len(h.Ranks()) != 2 || len(h.Suits()) != 2 {
+		t.Fatalf("Ranks() = %v, Suits() = %v; want no blank cards", h.Ranks(), h.Suits())
+	}
+}
+
+func TestHandImprove(t *testing.T) {
+	h := New(jokertest.Cards("Ah", "Kh", "7h", "4h", "2c"))
+	improved := h.Improve(NineHearts)
+	if improved.Ranking() != Flush || improved.Description() != "flush ace high" {
+		t.Fatalf("Improve() = %v; want flush ace high", improved)
+	}
+	if h.Ranking() != HighCard || h.UsesCard(NineHearts) {
+		t.Fatalf("Improve() changed the hand to %v", h)
+	}
+	if improved := h.Improve(ThreeDiamonds); improved.Ranking() != HighCard {
+		t.Fatalf("Improve() = %v; want %v", improved, HighCard)
+	}
+
+	low := New(jokertest.Cards("8h", "6d", "4c", "3s"), AceToFiveLow)
+	if improved := low.Improve(AceSpades); improved.Description() != "eight low" || !improved.UsesCard(AceSpades) {
+		t.Fatalf("Improve() = %v; want eight low with %v", improved, AceSpades)
+	}
+}
+
+func TestHandUsesCard(t *testing.T) {
+	cards := jokertest.Cards("Ks", "Kh", "7d", "7c", "Qs", "2h", "3d")
+	h := New(cards)
+	for _, c := range cards[:5] {
+		if !h.UsesCard(c) {
+			t.Fatalf("expected %v to use %v", h, c)
+		}
+	}
+	for _, c := range cards[5:] {
+		if h.UsesCard(c) {
+			t.Fatalf("expected %v to not use %v", h, c)
+		}
+	}
+	if card := *KingSpades; !h.UsesCard(&card) {
+		t.Fatalf("expected %v to use a copy of %v", h, KingSpades)
+	}
+}
+
+func TestHandJSON(t *testing.T) {
+	h := New(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"))
+
+	// to json
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and back
+	hCopy := &Hand{}
+	if err := json.Unmarshal(b, hCopy); err != nil {
+		t.Fatal(err)
+	}
+	if hCopy.Ranking() != h.Ranking() {
+		t.Fatalf("Ranking() = %v; want %v", hCopy.Ranking(), h.Ranking())
+	}
+	for i := 0; i < 5; i++ {
+		actual, expected := hCopy.Cards()[i], h.Cards()[i]
+		if actual.Rank() != expected.Rank() || actual.Suit() != expected.Suit() {
+			t.Fatalf("expected %v got %v", expected, actual)
+		}
+	}
+	if hCopy.Description() != h.Description() {
+		t.Fatalf("Description() = %q; want %q", hCopy.Description(), h.Description())
+	}
+}
+
+func TestHandBinary(t *testing.T) {
+	for _, s := range []string{
+		"As Ks Qs Js Ts",
+		"5h 4h 3h 2h Ah",
+		"Ac Ad Ah 7c 7d",
+		"Kd Kc 9s 9h 2c",
+		"Jd 8c 6s 4h 2c",
+		"Qs Qh 3d",
+		"2c",
+	} {
+		h, err := ParseHand(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := h.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 8 {
+			t.Fatalf("MarshalBinary() = %v; want 8 bytes", b)
+		}
+		hCopy := &Hand{}
+		if err := hCopy.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if hCopy.Ranking() != h.Ranking() || hCopy.String() != h.String() {
+			t.Fatalf("UnmarshalBinary(%v) = %v; want %v", b, hCopy, h)
+		}
+	}
+
+	// a straight stored as a royal flush
+	tampered := []byte{byte(RoyalFlush), 52, 48, 44, 40, 35, 0, 0}
+	invalid := [][]byte{tampered, {1, 2, 3}, {byte(HighCard), 53, 1, 5, 9, 13, 0, 0},
+		{byte(HighCard), 52, 48, 44, 40, 35}}
+	for _, b := range invalid {
+		if err := (&Hand{}).UnmarshalBinary(b); err == nil {
+			t.Fatalf("UnmarshalBinary(%v) should return an error", b)
+		}
+	}
+}
+
+func TestHandBinaryOptions(t *testing.T) {
+	tests := []struct {
+		cards   string
+		options []func(*Config)
+	}{
+		{"5h 4d 3c 2s Ah", []func(*Config){AceToFiveLow}},
+		{"5h 4d 3c 2s Ah", []func(*Config){DeuceToSevenLow}},
+		{"Ah 9d 8c 7s 6h", []func(*Config){ShortDeck}},
+		{"Ks Qs Js 9s 9h", []func(*Config){FlushSize(4)}},
+		{"Qh Kd Ac 2s 3h", []func(*Config){AroundTheCorner}},
+	}
+	for _, test := range tests {
+		h, err := ParseHand(test.cards, test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := h.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hCopy := &Hand{}
+		if err := hCopy.UnmarshalBinary(b); err != nil {
+			t.Fatalf("UnmarshalBinary(%v) error = %v", b, err)
+		}
+		if hCopy.String() != h.String() || hCopy.CompareTo(h) != 0 {
+			t.Fatalf("UnmarshalBinary(%v) = %v; want %v", b, hCopy, h)
+		}
+		if natural := New(h.Cards()); natural.String() == h.String() {
+			t.Fatalf("expected the options to change %v", h)
+		}
+	}
+
+	h := New(jokertest.Cards("As", "Ah", "Ad", "Ac", "2s"), WildRanks(Two))
+	if _, err := h.MarshalBinary(); err != ErrBinaryWild {
+		t.Fatalf("MarshalBinary() error = %v; want %v", err, ErrBinaryWild)
+	}
+}
+
+func TestCardText(t *testing.T) {
+	for _, card := range Cards() {
+		b, err := card.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardCopy := &Card{}
+		if err := cardCopy.UnmarshalText(b); err != nil {
+			t.Fatal(err)
+		}
+		if *cardCopy != *card {
+			t.Fatalf("UnmarshalText(%q) = %v; want %v", b, cardCopy, card)
+		}
+	}
+
+	for _, s := range []string{"", "A", "garbage", "1♠", "A♠♠", "As"} {
+		if err := (&Card{}).UnmarshalText([]byte(s)); err == nil {
+			t.Fatalf("UnmarshalText(%q) should return an error", s)
+		}
+	}
+
+	m := map[Card]int{*AceSpades: 1, *TwoHearts: 2}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mCopy := map[Card]int{}
+	if err := json.Unmarshal(b, &mCopy); err != nil {
+		t.Fatal(err)
+	}
+	if len(mCopy) != 2 || mCopy[*AceSpades] != 1 || mCopy[*TwoHearts] != 2 {
+		t.Fatalf("json.Unmarshal(%s) = %v; want %v", b, mCopy, m)
+	}
+}
+
+func TestHandJSONVerify(t *testing.T) {
+	const tampered = `{"ranking":10,"cards":["A♠","K♠","Q♠","J♠","9♠"],"description":"royal flush"}`
+	h := &Hand{}
+	if err := json.Unmarshal([]byte(tampered), h); err == nil {
+		t.Fatal("expected an error for a mismatched ranking")
+	}
+
+	VerifyJSONRanking = false
+	defer func() { VerifyJSONRanking = true }()
+	if err := json.Unmarshal([]byte(tampered), h); err != nil {
+		t.Fatal(err)
+	}
+	if h.Ranking() != Flush {
+		t.Fatalf("expected %v got %v", Flush, h.Ranking())
+	}
+}
+
+func TestHandJSONOptions(t *testing.T) {
+	tests := []struct {
+		cards   string
+		options []func(*Config)
+	}{
+		{"5h 4d 3c 2s Ah", []func(*Config){AceToFiveLow}},
+		{"5h 4d 3c 2s Ah", []func(*Config){NoWheel}},
+		{"Ah 9d 8c 7s 6h", []func(*Config){ShortDeck}},
+		{"Ks Qs Js 9s 9h", []func(*Config){FlushSize(4)}},
+		{"Qh Kd Ac 2s 3h", []func(*Config){AroundTheCorner}},
+	}
+	for _, test := range tests {
+		h, err := ParseHand(test.cards, test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hCopy := &Hand{}
+		if err := json.Unmarshal(b, hCopy); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error = %v", b, err)
+		}
+		if hCopy.String() != h.String() || hCopy.CompareTo(h) != 0 {
+			t.Fatalf("json.Unmarshal(%s) = %v; want %v", b, hCopy, h)
+		}
+	}
+
+	h := New(jokertest.Cards("As", "Ah", "Ad", "Ac", "2s"), WildRanks(Two))
+	if _, err := h.MarshalJSON(); err != ErrJSONWild {
+		t.Fatalf("MarshalJSON() error = %v; want %v", err, ErrJSONWild)
+	}
+	if _, err := json.Marshal(h); err == nil {
+		t.Fatalf("json.Marshal(%v) should return an error", h)
+	}
+
+	const invalid = `{"ranking":1,"cards":["A♠","K♠","Q♠","J♠","9♥"],"options":256}`
+	if err := json.Unmarshal([]byte(invalid), &Hand{}); err == nil {
+		t.Fatalf("json.Unmarshal(%s) should return an error", invalid)
+	}
+}
+
+func TestDescriptionWithKicker(t *testing.T) {
+	tests := map[string]string{
+		"As Ah Jd Jc Kh 2s 3d": "two pair aces and jacks, king kicker",
+		"As Ah Jd Jc 2h":       "two pair aces and jacks, two kicker",
+		"As Ah Jd Jc":          "two pair aces and jacks",
+		"As Ah Jd Tc 9h":       "pair of aces",
+	}
+	for s, expected := range tests {
+		h, err := ParseHand(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.DescriptionWithKicker() != expected {
+			t.Fatalf("DescriptionWithKicker() = %q; want %q", h.DescriptionWithKicker(), expected)
+		}
+	}
+	if h, _ := ParseHand("As Ah Jd Jc Kh"); h.Description() != "two pair aces and jacks" {
+		t.Fatalf("Description() = %q; want it unchanged", h.Description())
+	}
+}
+
+func TestVerboseDescription(t *testing.T) {
+	tests := map[string]string{
+		"As Ts 7s 4s 2s": "flush ace high in spades",
+		"9h 8h 7h 6h 5h": "straight flush nine high in hearts",
+		"Ad Kd Qd Jd Td": "royal flush in diamonds",
+		"Ac Kd Qd Jd Td": "straight ace high",
+		"Ac Ad Qd Jd Td": "pair of aces",
+	}
+	for s, expected := range tests {
+		h, err := ParseHand(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.VerboseDescription() != expected {
+			t.Fatalf("VerboseDescription() = %q; want %q", h.VerboseDescription(), expected)
+		}
+		if h.Description() == expected && h.Ranking() == Flush {
+			t.Fatalf("Description() shouldn't include the suit")
+		}
+	}
+}
+
+func TestBestHands(t *testing.T) {
+	cards := jokertest.Cards("Ah", "Kh", "Qh", "Jh", "9h", "Td", "2c")
+	hands := BestHands(cards, 3)
+	if len(hands) != 3 {
+		t.Fatalf("BestHands() = %v; want 3 hands", hands)
+	}
+	expected := []string{"flush ace high", "straight ace high", "straight king high"}
+	for i, h := range hands {
+		if h.Description() != expected[i] {
+			t.Fatalf("BestHands()[%d] = %v; want %v", i, h, expected[i])
+		}
+	}
+	if !hands[0].Ties(New(cards)) {
+		t.Fatalf("BestHands()[0] = %v; want %v", hands[0], New(cards))
+	}
+
+	if hands := BestHands(cards, 100); len(hands) != 21 {
+		t.Fatalf("BestHands() = %d hands; want %d", len(hands), 21)
+	}
+	// the five cards w/o a duplicate are only included once
+	if hands := BestHands(append(cards[:5:5], AceHearts), 100); len(hands) != 5 {
+		t.Fatalf("BestHands() = %d hands for a duplicate card; want %d", len(hands), 5)
+	}
+
+	low := BestHands(jokertest.Cards("Ah", "2d", "3c", "4s", "5h", "8c"), 2, AceToFiveLow)
+	if low[0].Description() != "five low" || low[1].Description() != "eight low" {
+		t.Fatalf("BestHands(AceToFiveLow) = %v", low)
+	}
+
+	defer func() {
+		if _, ok := recover().(error); !ok {
+			t.Fatal("BestHands() should panic with an error for an invalid card")
+		}
+	}()
+	BestHands(append(cards[:5:5], nil), 1)
+}
+
+func TestIs(t *testing.T) {
+	for _, test := range tests {
+		if len(test.cards) != 5 {
+			continue
+		}
+		for r := HighCard; r <= FiveOfAKind; r++ {
+			if Is(test.cards, r) != (r == test.ranking) {
+				t.Fatalf("Is(%v, %v) = %v", test.cards, r, Is(test.cards, r))
+			}
+		}
+	}
+
+	wheel := jokertest.Cards("Ah", "2d", "3c", "4s", "5h")
+	if !Is(wheel, Straight) || Is(wheel, Straight, NoWheel) || !Is(wheel, HighCard, NoWheel) {
+		t.Fatalf("Is() didn't apply options to %v", wheel)
+	}
+	if Is(wheel[:4], HighCard) || Is(append(wheel, KingSpades), Straight) {
+		t.Fatal("Is() should return false if not given five cards")
+	}
+}
+
+func TestRankingExample(t *testing.T) {
+	for r := HighCard; r <= RoyalFlush; r++ {
+		cards := r.Example()
+		if h := New(cards); h.Ranking() != r {
+			t.Fatalf("%v Example() = %v; formed %v", r, cards, h)
+		}
+	}
+	if cards := FiveOfAKind.Example(); New(cards, WildRanks(Two)).Ranking() != FiveOfAKind {
+		t.Fatalf("%v Example() = %v; formed %v", FiveOfAKind, cards, New(cards, WildRanks(Two)))
+	}
+	if cards := Ranking(0).Example(); cards != nil {
+		t.Fatalf("Ranking(0) Example() = %v; want nil", cards)
+	}
+}
+
+func TestRankingJSON(t *testing.T) {
+	names := []string{"high card", "pair", "two pair", "three of a kind", "straight",
+		"flush", "full house", "four of a kind", "straight flush", "royal flush"}
+	for i, name := range names {
+		r := Ranking(i + 1)
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != fmt.Sprint(i+1) {
+			t.Fatalf("json.Marshal(%v) = %s; want %d", r, b, i+1)
+		}
+
+		MarshalRankingNames = true
+		b, err = json.Marshal(r)
+		MarshalRankingNames = false
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `"`+name+`"` {
+			t.Fatalf("json.Marshal(%v) = %s; want %q", r, b, name)
+		}
+
+		for _, s := range []string{fmt.Sprint(i + 1), `"` + name + `"`} {
+			var rCopy Ranking
+			if err := json.Unmarshal([]byte(s), &rCopy); err != nil {
+				t.Fatal(err)
+			}
+			if rCopy != r {
+				t.Fatalf("json.Unmarshal(%s) = %v; want %v", s, rCopy, r)
+			}
+		}
+	}
+
+	var r Ranking
+	if err := json.Unmarshal([]byte(`"royal"`), &r); err == nil {
+		t.Fatal("expected an error for an unknown ranking name")
+	}
+
+	MarshalRankingNames = true
+	defer func() { MarshalRankingNames = false }()
+	h := New(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"))
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"ranking":"royal flush","cards":["A♠","K♠","Q♠","J♠","T♠"],"description":"royal flush"}`
+	if string(b) != expected {
+		t.Fatalf("json.Marshal() = %s; want %s", b, expected)
+	}
+	hCopy := &Hand{}
+	if err := json.Unmarshal(b, hCopy); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkHandCreation(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cards := NewDealer().Deck().PopMulti(7)
+		New(cards)
+	}
+}
+
+func TestMaxCards(t *testing.T) {
+	h, err := NewErr(jokertest.Cards("2s", "4h", "6d", "8c", "Ts", "Qh", "Kd", "Ac", "3s"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Ranking() != HighCard || h.Description() != "high card ace high" {
+		t.Fatalf("expected high card ace high got %v", h)
+	}
+
+	h = New(jokertest.Cards("2s", "4h", "6d", "8c", "Ts", "Qh", "Kd", "Ac", "3s", "5h"))
+	if h.Ranking() != Straight || h.Description() != "straight six high" {
+		t.Fatalf("expected straight six high got %v", h)
+	}
+
+	cards := jokertest.Cards("2s", "4h", "6d", "8c", "Ts", "Qh", "Kd", "Ac", "3s", "5h", "7h")
+	if _, err := NewErr(cards); err == nil {
+		t.Fatalf("NewErr() should return an error for %d cards", len(cards))
+	}
+}
+
+func TestNewErrUnrankableCards(t *testing.T) {
+	for _, s := range []string{"As As As As As Ks", "As As As As As Ks Qs"} {
+		cards, err := ParseCards(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h, err := NewErr(cards); err == nil {
+			t.Fatalf("NewErr(%v) = %v; want an error", cards, h)
+		}
+	}
+}
+
+func BenchmarkNineCardHand(b *testing.B) {
+	cards := jokertest.Cards("As", "Kd", "Qh", "7c", "7s", "2h", "3d", "9s", "Tc")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(cards)
+	}
+}
+
+func BenchmarkSevenCardHand(b *testing.B) {
+	cards := jokertest.Cards("As", "Kd", "Qh", "7c", "7s", "2h", "3d")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
 		New(cards)
 	}
 }
@@ -0,0 +1,92 @@
+package hand
+
+// A Localizer provides the words used in hand descriptions so that they
+// can be translated.  Ranking.String is unaffected by the Localizer.
+type Localizer interface {
+	// SingularName returns the name of the rank in singular form such as
+	// "two" for Two.
+	SingularName(r Rank) string
+
+	// PluralName returns the name of the rank in plural form such as
+	// "twos" for Two.
+	PluralName(r Rank) string
+
+	// Format returns the fmt format used to describe a hand of the
+	// ranking.  The format is given the same rank names, in the same
+	// order, as the English format.  For example, the English format for
+	// FullHouse is "full house %v full of %v" and is given the plural
+	// names of the three of a kind and pair ranks.
+	Format(r Ranking) string
+
+	// LowFormat returns the fmt format used to describe an ace to five
+	// low hand.  It is given the singular name of the highest rank.
+	LowFormat() string
+
+	// KickerFormat returns the fmt format used by DescriptionWithKicker.
+	// It is given the hand's description and the singular name of the
+	// kicker's rank.
+	KickerFormat() string
+
+	// SuitName returns the name of the suit such as "spades" for Spades.
+	SuitName(s Suit) string
+
+	// SuitFormat returns the fmt format used by VerboseDescription.  It
+	// is given the hand's description and the name of the flush's suit.
+	SuitFormat() string
+}
+
+var localizer Localizer = english{}
+
+// SetLocalizer sets the Localizer used by hands formed afterwards.  The
+// default Localizer is English.  SetLocalizer isn't safe to call while
+// hands are being formed.
+func SetLocalizer(l Localizer) {
+	if l == nil {
+		l = english{}
+	}
+	localizer = l
+}
+
+type english struct{}
+
+func (english) SingularName(r Rank) string {
+	return singularNames[r]
+}
+
+func (english) PluralName(r Rank) string {
+	return pluralNames[r]
+}
+
+func (english) Format(r Ranking) string {
+	return englishFormats[r]
+}
+
+func (english) LowFormat() string {
+	return "%v low"
+}
+
+func (english) KickerFormat() string {
+	return "%v, %v kicker"
+}
+
+func (english) SuitName(s Suit) string {
+	return suitNames[s]
+}
+
+func (english) SuitFormat() string {
+	return "%v in %v"
+}
+
+var englishFormats = map[Ranking]string{
+	HighCard:      "high card %v high",
+	Pair:          "pair of %v",
+	TwoPair:       "two pair %v and %v",
+	ThreeOfAKind:  "three of a kind %v",
+	Straight:      "straight %v high",
+	Flush:         "flush %v high",
+	FullHouse:     "full house %v full of %v",
+	FourOfAKind:   "four of a kind %v",
+	StraightFlush: "straight flush %v high",
+	RoyalFlush:    "royal flush",
+	FiveOfAKind:   "five of a kind %v",
+}
@@ -0,0 +1,73 @@
+package hand_test
+
+import (
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+type spanish struct{}
+
+func (spanish) SingularName(r Rank) string {
+	return map[Rank]string{King: "rey", Nine: "nueve", Two: "dos"}[r]
+}
+
+func (spanish) PluralName(r Rank) string {
+	return map[Rank]string{King: "reyes", Nine: "nueves", Two: "doses"}[r]
+}
+
+func (spanish) Format(r Ranking) string {
+	return map[Ranking]string{
+		TwoPair:       "doble pareja de %v y %v",
+		StraightFlush: "escalera de color al %v",
+		FullHouse:     "full de %v y %v",
+	}[r]
+}
+
+func (spanish) LowFormat() string {
+	return "%v bajo"
+}
+
+func (spanish) KickerFormat() string {
+	return "%v, con %v"
+}
+
+func (spanish) SuitName(s Suit) string {
+	return map[Suit]string{Spades: "picas"}[s]
+}
+
+func (spanish) SuitFormat() string {
+	return "%v de %v"
+}
+
+func TestLocalizer(t *testing.T) {
+	SetLocalizer(spanish{})
+	defer SetLocalizer(nil)
+
+	h := New(jokertest.Cards("Ks", "Qs", "Js", "Ts", "9s"))
+	if h.Description() != "escalera de color al rey" {
+		t.Fatalf("expected \"escalera de color al rey\" got \"%v\"", h.Description())
+	}
+	if h.VerboseDescription() != "escalera de color al rey de picas" {
+		t.Fatalf("VerboseDescription() = %q; want %q", h.VerboseDescription(), "escalera de color al rey de picas")
+	}
+	h = New(jokertest.Cards("Ks", "Kh", "Kd", "9s", "9c"))
+	if h.Description() != "full de reyes y nueves" {
+		t.Fatalf("expected \"full de reyes y nueves\" got \"%v\"", h.Description())
+	}
+	if h.Ranking().String() != "FullHouse" {
+		t.Fatalf("Ranking().String() = %q; want %q", h.Ranking().String(), "FullHouse")
+	}
+
+	h = New(jokertest.Cards("Ks", "Kh", "9d", "9s", "2c"))
+	if h.DescriptionWithKicker() != "doble pareja de reyes y nueves, con dos" {
+		t.Fatalf("DescriptionWithKicker() = %q; want %q", h.DescriptionWithKicker(), "doble pareja de reyes y nueves, con dos")
+	}
+
+	SetLocalizer(nil)
+	h = New(jokertest.Cards("Ks", "Kh", "Kd", "9s", "9c"))
+	if h.Description() != "full house kings full of nines" {
+		t.Fatalf("expected \"full house kings full of nines\" got \"%v\"", h.Description())
+	}
+}
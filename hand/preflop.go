@@ -0,0 +1,221 @@
+package hand
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// StartingHandClass returns the standard label of the hole cards such as
+// "QQ" for a pair, "AKs" for suited cards, or "AKo" for offsuit cards.
+// The higher rank is always first so the order of the hole cards
+// doesn't matter.
+func StartingHandClass(hole [2]*Card) string {
+	high, low := hole[0], hole[1]
+	if low.Rank().indexOf() > high.Rank().indexOf() {
+		high, low = low, high
+	}
+	class := string(high.Rank()) + string(low.Rank())
+	switch {
+	case high.Rank() == low.Rank():
+		return class
+	case high.Suit() == low.Suit():
+		return class + "s"
+	}
+	return class + "o"
+}
+
+// RangeEquity estimates the hero's Texas Hold'em equity against a villain
+// holding a random hand from the range.  The range is a list of tokens in
+// the format accepted by ExpandRange such as "TT+" or "AKs".  Villain
+// hands that share a card with the hero are excluded.  Passing a
+// rand.Rand with a fixed seed produces reproducible results.
+func RangeEquity(hero [2]*Card, villainRange []string, iterations int, r *rand.Rand) (EquityResult, error) {
+	combos, err := ExpandRange(strings.Join(villainRange, ","))
+	if err != nil {
+		return EquityResult{}, err
+	}
+	combos = withoutCards(combos, hero[:])
+	if len(combos) == 0 {
+		return EquityResult{}, errors.New("hand: villain range has no hands")
+	}
+
+	remaining := RemainingCards(hero[:])
+	results := make([]EquityResult, 2)
+	board := make([]*Card, 0, 5)
+	for i := 0; i < iterations; i++ {
+		villain := combos[r.Intn(len(combos))]
+		// partial Fisher-Yates shuffle skipping the villain's cards
+		board = board[:0]
+		for dealt := 0; len(board) < 5; dealt++ {
+			j := dealt + r.Intn(len(remaining)-dealt)
+			remaining[dealt], remaining[j] = remaining[j], remaining[dealt]
+			if c := remaining[dealt]; !c.Equal(villain[0]) && !c.Equal(villain[1]) {
+				board = append(board, c)
+			}
+		}
+		showdown([][]*Card{hero[:], villain[:]}, board, results)
+	}
+	return averageResults(results, iterations)[0], nil
+}
+
+// ExpandRange returns every combination of hole cards in the comma
+// separated range such as "QQ+, AKs, 76s-54s".  Each token is a starting
+// hand class like "AA", "AKs", "AKo", or "AK" for both.  A "+" suffix
+// includes higher pairs such as "TT+" or higher kickers such as "ATs+".
+// A "-" joins two classes of the same kind to include the classes between
+// them such as "99-66", "A9s-A6s", or "76s-54s".  Combinations are only
+// returned once.
+func ExpandRange(spec string) ([][2]*Card, error) {
+	combos := [][2]*Card{}
+	for _, token := range strings.Split(spec, ",") {
+		tokenCombos, err := expandToken(strings.TrimSpace(token))
+		if err != nil {
+			return nil, err
+		}
+		combos = append(combos, tokenCombos...)
+	}
+	return withoutCards(combos, nil), nil
+}
+
+// expandToken returns every combination of hole cards in a single token
+// of a range.
+func expandToken(token string) ([][2]*Card, error) {
+	errToken := fmt.Errorf("hand: can't parse hand range token %q", token)
+	if strings.Contains(token, "-") {
+		parts := strings.Split(token, "-")
+		if len(parts) != 2 {
+			return nil, errToken
+		}
+		c1, err1 := parseClass(parts[0])
+		c2, err2 := parseClass(parts[1])
+		if err1 != nil || err2 != nil || c1.suited != c2.suited || c1.offsuit != c2.offsuit {
+			return nil, errToken
+		}
+		if c2.high.indexOf() > c1.high.indexOf() || c2.low.indexOf() > c1.low.indexOf() {
+			c1, c2 = c2, c1
+		}
+		pairs := c1.high == c1.low && c2.high == c2.low
+		sameHigh := c1.high == c2.high && c1.high != c1.low && c2.high != c2.low
+		sameGap := c1.high.indexOf()-c1.low.indexOf() == c2.high.indexOf()-c2.low.indexOf()
+		if !pairs && !sameHigh && !sameGap {
+			return nil, errToken
+		}
+		return c1.between(c2), nil
+	}
+
+	plus := strings.HasSuffix(token, "+")
+	c, err := parseClass(strings.TrimSuffix(token, "+"))
+	if err != nil {
+		return nil, errToken
+	}
+	if !plus {
+		return c.between(c), nil
+	}
+	top := c
+	if c.high == c.low {
+		top.high, top.low = Ace, Ace
+	} else {
+		top.low = allRanks()[c.high.indexOf()-1]
+	}
+	return top.between(c), nil
+}
+
+// handClass is a starting hand class such as "AKs".
+type handClass struct {
+	high, low       Rank
+	suited, offsuit bool
+}
+
+func parseClass(s string) (handClass, error) {
+	errClass := fmt.Errorf("hand: can't parse hand class %q", s)
+	runes := []rune(s)
+	if len(runes) != 2 && len(runes) != 3 {
+		return handClass{}, errClass
+	}
+	high, hErr := ParseRank(string(runes[0]))
+	low, lErr := ParseRank(string(runes[1]))
+	if hErr != nil || lErr != nil {
+		return handClass{}, errClass
+	}
+	if low.indexOf() > high.indexOf() {
+		high, low = low, high
+	}
+	c := handClass{high: high, low: low, suited: true, offsuit: true}
+	if len(runes) == 3 {
+		switch {
+		case high == low:
+			return handClass{}, errClass
+		case runes[2] == 's' || runes[2] == 'S':
+			c.offsuit = false
+		case runes[2] == 'o' || runes[2] == 'O':
+			c.suited = false
+		default:
+			return handClass{}, errClass
+		}
+	}
+	return c, nil
+}
+
+// between returns the combinations of the classes from c down to the
+// lower class o, stepping down the ranks of c that differ from o.
+func (c handClass) between(o handClass) [][2]*Card {
+	ranks := allRanks()
+	combos := [][2]*Card{}
+	high, low := c.high.indexOf(), c.low.indexOf()
+	stepHigh, stepLow := c.high != o.high, c.low != o.low
+	for low >= o.low.indexOf() && high >= o.high.indexOf() {
+		combos = append(combos, rankCombos(ranks[high], ranks[low], c.suited, c.offsuit)...)
+		if !stepHigh && !stepLow {
+			break
+		}
+		if stepHigh {
+			high--
+		}
+		if stepLow {
+			low--
+		}
+	}
+	return combos
+}
+
+// rankCombos returns the hole cards of the two ranks that are suited or
+// offsuit as requested.  Pairs are always offsuit.
+func rankCombos(high, low Rank, suited, offsuit bool) [][2]*Card {
+	combos := [][2]*Card{}
+	suits := allSuits()
+	for i, s1 := range suits {
+		for j, s2 := range suits {
+			// pairs only need each pair of suits once
+			if high == low && j <= i {
+				continue
+			}
+			if (s1 == s2 && suited) || (s1 != s2 && offsuit) {
+				combos = append(combos, [2]*Card{NewCard(high, s1), NewCard(low, s2)})
+			}
+		}
+	}
+	return combos
+}
+
+// withoutCards returns the combos that don't share a card with cards or
+// repeat an earlier combo.
+func withoutCards(combos [][2]*Card, cards []*Card) [][2]*Card {
+	seen := map[[2]*Card]bool{}
+	filtered := [][2]*Card{}
+	for _, combo := range combos {
+		if seen[combo] {
+			continue
+		}
+		seen[combo] = true
+		blocked := false
+		for _, c := range cards {
+			blocked = blocked || c.Equal(combo[0]) || c.Equal(combo[1])
+		}
+		if !blocked {
+			filtered = append(filtered, combo)
+		}
+	}
+	return filtered
+}
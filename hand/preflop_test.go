@@ -0,0 +1,111 @@
+package hand_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+)
+
+func TestStartingHandClass(t *testing.T) {
+	tests := []struct {
+		hole  [2]*Card
+		class string
+	}{
+		{[2]*Card{AceSpades, KingSpades}, "AKs"},
+		{[2]*Card{KingSpades, AceSpades}, "AKs"},
+		{[2]*Card{KingHearts, AceSpades}, "AKo"},
+		{[2]*Card{QueenHearts, QueenClubs}, "QQ"},
+		{[2]*Card{TwoDiamonds, TenDiamonds}, "T2s"},
+		{[2]*Card{SevenClubs, SixHearts}, "76o"},
+	}
+	for _, test := range tests {
+		if class := StartingHandClass(test.hole); class != test.class {
+			t.Fatalf("StartingHandClass(%v) = %q; want %q", test.hole, class, test.class)
+		}
+	}
+}
+
+func TestRangeEquity(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	aces := [2]*Card{AceSpades, AceHearts}
+	tests := []struct {
+		villainRange []string
+		equity       float64
+	}{
+		{[]string{"AA"}, 0.5},
+		{[]string{"KK"}, 0.82},
+		{[]string{"72o"}, 0.88},
+		{[]string{"TT+", "AKs"}, 0.80},
+	}
+	for _, test := range tests {
+		result, err := RangeEquity(aces, test.villainRange, 1000, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(result.Equity-test.equity) > 0.05 {
+			t.Fatalf("RangeEquity(%v, %v) = %+v; want equity near %v", aces, test.villainRange, result, test.equity)
+		}
+	}
+
+	for _, villainRange := range [][]string{{"AX"}, {"AAs"}, {"AKx"}, {"A"}, {"AKs++"}} {
+		if _, err := RangeEquity(aces, villainRange, 10, r); err == nil {
+			t.Fatalf("RangeEquity(%v) should return an error", villainRange)
+		}
+	}
+	if _, err := RangeEquity([2]*Card{AceSpades, KingSpades}, []string{"AKs"}, 10, r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpandRange(t *testing.T) {
+	tests := map[string]int{
+		"AA":                6,
+		"AKs":               4,
+		"AKo":               12,
+		"KA":                16,
+		"QQ+":               18,
+		"ATs+":              16,
+		"76s-54s":           12,
+		"54s-76s":           12,
+		"99-66":             24,
+		"A9o-A6o":           48,
+		"QQ+, AKs, 76s-54s": 34,
+		"AK,AKs":            16,
+	}
+	for spec, n := range tests {
+		combos, err := ExpandRange(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(combos) != n {
+			t.Fatalf("ExpandRange(%q) = %d combos; want %d", spec, len(combos), n)
+		}
+	}
+
+	combos, _ := ExpandRange("76s-54s")
+	for _, combo := range combos {
+		if class := StartingHandClass(combo); class != "76s" && class != "65s" && class != "54s" {
+			t.Fatalf("ExpandRange(%q) returned %v", "76s-54s", combo)
+		}
+	}
+
+	invalid := map[string]string{
+		"":            "",
+		"AA, XX":      "XX",
+		"AKs-QJo":     "AKs-QJo",
+		"AA-KQ":       "AA-KQ",
+		"AKs-T9s-98s": "AKs-T9s-98s",
+		"K9s-A6s":     "K9s-A6s",
+		"AAs, KK":     "AAs",
+	}
+	for spec, token := range invalid {
+		_, err := ExpandRange(spec)
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("%q", token)) {
+			t.Fatalf("ExpandRange(%q) error = %v; want an error naming %q", spec, err, token)
+		}
+	}
+}
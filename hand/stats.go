@@ -0,0 +1,50 @@
+package hand
+
+import "math/rand"
+
+// Histogram deals the given number of random hands, each formed from
+// cardsPerHand cards of a freshly shuffled deck, and returns the number
+// of hands of each ranking.  Passing a rand.Rand with a fixed seed
+// produces reproducible results.  Histogram panics with ErrDeckExhausted
+// if cardsPerHand is greater than 52.
+func Histogram(iterations int, cardsPerHand int, r *rand.Rand) map[Ranking]int {
+	counts := map[Ranking]int{}
+	for i := 0; i < iterations; i++ {
+		deck := NewDeck()
+		deck.Shuffle(r.Shuffle)
+		cards, err := deck.Draw(cardsPerHand)
+		if err != nil {
+			panic(err)
+		}
+		counts[New(cards).Ranking()]++
+	}
+	return counts
+}
+
+// EnumerateFrequencies evaluates every one of the 2,598,960 five card
+// hands and returns the number of hands of each ranking.
+func EnumerateFrequencies() map[Ranking]int {
+	fastEvalOnce.Do(initFastEval)
+	cards := Cards()
+	counts := make([]int, len(rankingNames)+1)
+	for a := 0; a < len(cards); a++ {
+		for b := a + 1; b < len(cards); b++ {
+			for c := b + 1; c < len(cards); c++ {
+				for d := c + 1; d < len(cards); d++ {
+					for e := d + 1; e < len(cards); e++ {
+						v := fastEval(cards[a], cards[b], cards[c], cards[d], cards[e])
+						counts[valueRankings[v]]++
+					}
+				}
+			}
+		}
+	}
+
+	frequencies := map[Ranking]int{}
+	for r, n := range counts {
+		if n > 0 {
+			frequencies[Ranking(r)] = n
+		}
+	}
+	return frequencies
+}
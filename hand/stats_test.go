@@ -0,0 +1,66 @@
+package hand_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+)
+
+func TestHistogram(t *testing.T) {
+	const iterations = 5000
+	counts := Histogram(iterations, 5, rand.New(rand.NewSource(5)))
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if total != iterations {
+		t.Fatalf("histogram total = %d; want %d", total, iterations)
+	}
+
+	// published five card frequencies
+	expected := map[Ranking]float64{HighCard: 0.501, Pair: 0.423, TwoPair: 0.0475}
+	for r, freq := range expected {
+		actual := float64(counts[r]) / iterations
+		if math.Abs(actual-freq) > 0.03 {
+			t.Fatalf("%v frequency = %v; want about %v", r, actual, freq)
+		}
+	}
+
+	again := Histogram(iterations, 5, rand.New(rand.NewSource(5)))
+	for r, n := range counts {
+		if again[r] != n {
+			t.Fatalf("Histogram() = %v; want %v", again, counts)
+		}
+	}
+}
+
+func TestEnumerateFrequencies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping enumeration of every five card hand")
+	}
+	expected := map[Ranking]int{
+		HighCard:      1302540,
+		Pair:          1098240,
+		TwoPair:       123552,
+		ThreeOfAKind:  54912,
+		Straight:      10200,
+		Flush:         5108,
+		FullHouse:     3744,
+		FourOfAKind:   624,
+		StraightFlush: 36,
+		RoyalFlush:    4,
+	}
+	frequencies := EnumerateFrequencies()
+	total := 0
+	for r, n := range frequencies {
+		total += n
+		if n != expected[r] {
+			t.Fatalf("%v frequency = %d; want %d", r, n, expected[r])
+		}
+	}
+	if total != 2598960 {
+		t.Fatalf("total = %d; want %d", total, 2598960)
+	}
+}
@@ -4,13 +4,14 @@ package hand
 
 import "fmt"
 
-const _Ranking_name = "HighCardPairTwoPairThreeOfAKindStraightFlushFullHouseFourOfAKindStraightFlushRoyalFlush"
+const _Ranking_name = "HighCardPairTwoPairThreeOfAKindStraightFlushFullHouseFourOfAKindStraightFlushRoyalFlushFiveOfAKind"
 
-var _Ranking_index = [...]uint8{8, 12, 19, 31, 39, 44, 53, 64, 77, 87}
+var _Ranking_index = [...]uint8{8, 12, 19, 31, 39, 44, 53, 64, 77, 87, 98}
 
 func (i Ranking) String() string {
+	i -= 1
 	if i < 0 || i >= Ranking(len(_Ranking_index)) {
-		return fmt.Sprintf("Ranking(%d)", i)
+		return fmt.Sprintf("Ranking(%d)", i+1)
 	}
 	hi := _Ranking_index[i]
 	lo := uint8(0)
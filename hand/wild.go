@@ -0,0 +1,156 @@
+package hand
+
+// Wild configures NewHand to treat every card for which isWild returns
+// true as a wild card.  Each wild card is replaced with whichever rank
+// and suit forms the best hand, which makes FiveOfAKind possible.  For
+// example, deuces wild is:
+//
+//	hand.New(cards, hand.Wild(func(c *hand.Card) bool {
+//		return c.Rank() == hand.Two
+//	}))
+func Wild(isWild func(*Card) bool) func(*Config) {
+	return func(c *Config) {
+		c.isWild = isWild
+	}
+}
+
+// WildRanks configures NewHand to treat every card of the given ranks as
+// a wild card the same as Wild.  For example, deuces wild is
+// hand.WildRanks(hand.Two).
+func WildRanks(ranks ...Rank) func(*Config) {
+	ranks = append([]Rank{}, ranks...)
+	return Wild(func(c *Card) bool {
+		for _, r := range ranks {
+			if c.Rank() == r {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Bug configures NewHand to treat every card for which isBug returns
+// true as a bug.  A bug is a partially wild card that may only be used
+// as an ace or to complete a straight or flush.
+func Bug(isBug func(*Card) bool) func(*Config) {
+	return func(c *Config) {
+		c.isWild = isBug
+		c.bug = true
+	}
+}
+
+// BestWithCommunityWild returns the best hand formed from the cards and a
+// single shared wild card that takes whichever rank and suit forms the
+// best hand.  Only the given wild card is wild, even if the cards include
+// a card of the same rank and suit.
+func BestWithCommunityWild(cards []*Card, wild *Card, options ...func(*Config)) *Hand {
+	w := *wild
+	all := append(append([]*Card{}, cards...), &w)
+	options = append(options[:len(options):len(options)], Wild(func(c *Card) bool {
+		return c == &w
+	}))
+	return New(all, options...)
+}
+
+// wildHandForFiveCards returns the best hand formed by substituting each
+// wild card in cards with a natural card.
+func wildHandForFiveCards(cards []*Card, c Config) (*Hand, error) {
+	natural := []*Card{}
+	for _, card := range cards {
+		if !c.isWild(card) {
+			natural = append(natural, card)
+		}
+	}
+	wilds := len(cards) - len(natural)
+	if wilds == 0 {
+		return rankedHand(cards, c)
+	}
+
+	subs := substitutes(natural)
+	formed := make([]*Card, len(natural), len(cards))
+	copy(formed, natural)
+
+	var best *Hand
+	var substitute func(start, left int) error
+	substitute = func(start, left int) error {
+		if left == 0 {
+			// rankedHand sorts the cards so pass it a copy
+			h, err := rankedHand(append([]*Card{}, formed...), c)
+			if err != nil {
+				return err
+			}
+			if c.bug && !bugAllowed(h, formed[len(natural):]) {
+				return nil
+			}
+			if best == nil || isBetter(h, best, c.sorting) {
+				best = h
+			}
+			return nil
+		}
+		// wild cards are interchangeable so substitutes are chosen in
+		// order to avoid trying the same set more than once
+		for i := start; i < len(subs); i++ {
+			formed = append(formed, subs[i])
+			if err := substitute(i, left-1); err != nil {
+				return err
+			}
+			formed = formed[:len(formed)-1]
+		}
+		return nil
+	}
+	if err := substitute(0, wilds); err != nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+// bugAllowed returns true if the substitutes used for bugs form the hand
+// by either being aces or completing a straight or flush.
+func bugAllowed(h *Hand, subs []*Card) bool {
+	switch h.Ranking() {
+	case Straight, Flush, StraightFlush, RoyalFlush:
+		return true
+	}
+	for _, s := range subs {
+		if s.Rank() != Ace {
+			return false
+		}
+	}
+	return true
+}
+
+// substitutes returns the cards a wild card could be replaced with.  Only
+// the suits of the natural cards and one other suit are needed since any
+// other suit would form an equivalent hand.
+func substitutes(natural []*Card) []*Card {
+	suits := []Suit{}
+	for _, s := range allSuits() {
+		for _, c := range natural {
+			if c.Suit() == s {
+				suits = append(suits, s)
+				break
+			}
+		}
+	}
+	for _, s := range allSuits() {
+		if len(suits) == len(allSuits()) {
+			break
+		}
+		found := false
+		for _, suit := range suits {
+			found = found || suit == s
+		}
+		if !found {
+			suits = append(suits, s)
+			break
+		}
+	}
+
+	subs := []*Card{}
+	for _, r := range allRanks() {
+		for _, s := range suits {
+			subs = append(subs, &Card{rank: r, suit: s})
+		}
+	}
+	return subs
+}
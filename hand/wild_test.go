@@ -0,0 +1,191 @@
+package hand_test
+
+import (
+	"testing"
+
+	. "github.com/notnil/joker/hand"
+	"github.com/notnil/joker/jokertest"
+)
+
+var deucesWild = Wild(func(c *Card) bool {
+	return c.Rank() == Two
+})
+
+type testWild struct {
+	cards       []*Card
+	ranking     Ranking
+	description string
+}
+
+var wildTests = []testWild{
+	{
+		jokertest.Cards("As", "Ah", "Ad", "Ac", "2s"),
+		FiveOfAKind,
+		"five of a kind aces",
+	},
+	{
+		jokertest.Cards("Ks", "Qs", "Js", "2h", "9d"),
+		Straight,
+		"straight king high",
+	},
+	{
+		jokertest.Cards("Ks", "Qs", "Js", "2h", "Ts"),
+		RoyalFlush,
+		"royal flush",
+	},
+	{
+		jokertest.Cards("7s", "7h", "2c", "2d", "4s"),
+		FourOfAKind,
+		"four of a kind sevens",
+	},
+	{
+		jokertest.Cards("Kh", "9s", "6d", "4c", "2h"),
+		Pair,
+		"pair of kings",
+	},
+	{
+		jokertest.Cards("Kh", "9h", "6h", "4h", "2s", "3c", "3d"),
+		Flush,
+		"flush ace high",
+	},
+	{
+		jokertest.Cards("2s", "2h", "2d", "2c", "9h"),
+		FiveOfAKind,
+		"five of a kind nines",
+	},
+}
+
+func TestWild(t *testing.T) {
+	for _, test := range wildTests {
+		h := New(test.cards, deucesWild)
+		if h.Ranking() != test.ranking {
+			t.Fatalf("New(%v) = %v; want %v", test.cards, h, test.ranking)
+		}
+		if test.description != h.Description() {
+			t.Fatalf("expected \"%v\" got \"%v\"", test.description, h.Description())
+		}
+	}
+
+	// without wilds the natural hand is unchanged
+	h := New(jokertest.Cards("As", "Ah", "Ad", "Ac", "2s"))
+	if h.Ranking() != FourOfAKind {
+		t.Fatalf("expected %v got %v", FourOfAKind, h.Ranking())
+	}
+
+	five := New(jokertest.Cards("As", "Ah", "Ad", "Ac", "2s"), deucesWild)
+	royal := New(jokertest.Cards("As", "Ks", "Qs", "Js", "Ts"), deucesWild)
+	if !five.Beats(royal) {
+		t.Fatalf("expected %v to beat %v", five, royal)
+	}
+}
+
+func TestFiveOfAKind(t *testing.T) {
+	naturals := jokertest.Cards("Kh", "Ks", "Kd", "Kc")
+	for _, wild := range jokertest.Cards("2h", "2s", "2d", "2c") {
+		h := New(append(naturals, wild), deucesWild)
+		if h.Ranking() != FiveOfAKind {
+			t.Fatalf("expected %v got %v", FiveOfAKind, h.Ranking())
+		}
+		if h.Description() != "five of a kind kings" {
+			t.Fatalf("expected \"five of a kind kings\" got \"%v\"", h.Description())
+		}
+	}
+
+	// five cards of a rank without wilds aren't a valid hand
+	dup := &Card{}
+	if err := dup.UnmarshalText([]byte("K♥")); err != nil {
+		t.Fatal(err)
+	}
+	if h, err := NewErr(append(naturals, dup)); err == nil {
+		t.Fatalf("NewErr() = %v; want an error", h)
+	}
+}
+
+func TestRankingString(t *testing.T) {
+	names := []string{"HighCard", "Pair", "TwoPair", "ThreeOfAKind", "Straight",
+		"Flush", "FullHouse", "FourOfAKind", "StraightFlush", "RoyalFlush", "FiveOfAKind"}
+	for i, name := range names {
+		if s := Ranking(i + 1).String(); s != name {
+			t.Fatalf("Ranking(%d).String() = %q; want %q", i+1, s, name)
+		}
+	}
+	if s := Ranking(0).String(); s != "Ranking(0)" {
+		t.Fatalf("Ranking(0).String() = %q; want %q", s, "Ranking(0)")
+	}
+}
+
+var jokerBug = Bug(func(c *Card) bool {
+	return c == TwoClubs
+})
+
+var bugTests = []testWild{
+	{
+		jokertest.Cards("Kh", "9h", "6h", "4h", "2c"),
+		Flush,
+		"flush ace high",
+	},
+	{
+		jokertest.Cards("Kh", "Qd", "Jh", "9s", "2c"),
+		Straight,
+		"straight king high",
+	},
+	{
+		jokertest.Cards("4h", "4s", "9d", "Kc", "2c"),
+		Pair,
+		"pair of fours",
+	},
+	{
+		jokertest.Cards("4h", "4s", "Ad", "9c", "2c"),
+		TwoPair,
+		"two pair aces and fours",
+	},
+	{
+		jokertest.Cards("Ah", "As", "Ad", "Ac", "2c"),
+		FiveOfAKind,
+		"five of a kind aces",
+	},
+}
+
+func TestBug(t *testing.T) {
+	for _, test := range bugTests {
+		h := New(test.cards, jokerBug)
+		if h.Ranking() != test.ranking {
+			t.Fatalf("New(%v) = %v; want %v", test.cards, h, test.ranking)
+		}
+		if test.description != h.Description() {
+			t.Fatalf("expected \"%v\" got \"%v\"", test.description, h.Description())
+		}
+	}
+}
+
+func TestWildRanks(t *testing.T) {
+	h := New(jokertest.Cards("Qs", "Qh", "Qd", "Qc", "2s"), WildRanks(Two))
+	if h.Ranking() != FiveOfAKind || h.Description() != "five of a kind queens" {
+		t.Fatalf("New() = %v; want five of a kind queens", h)
+	}
+
+	// both hands are formed with the same wild ranks
+	opts := WildRanks(Two, Three)
+	h1 := New(jokertest.Cards("Ks", "Kh", "3d", "7c", "8s"), opts)
+	h2 := New(jokertest.Cards("As", "Ah", "2d", "7d", "8h"), opts)
+	if h1.Ranking() != ThreeOfAKind || !h2.Beats(h1) {
+		t.Fatalf("expected %v to beat %v", h2, h1)
+	}
+	if h := New(jokertest.Cards("Ks", "Kh", "4d", "7c", "8s"), opts); h.Ranking() != Pair {
+		t.Fatalf("New() = %v; want %v", h, Pair)
+	}
+}
+
+func TestBestWithCommunityWild(t *testing.T) {
+	cards := jokertest.Cards("As", "Ks", "Qs", "Js", "4d", "3c")
+	h := BestWithCommunityWild(cards, SevenHearts)
+	if h.Ranking() != RoyalFlush {
+		t.Fatalf("BestWithCommunityWild() = %v; want %v", h, RoyalFlush)
+	}
+
+	// only the community card is wild, not other cards of its rank and suit
+	h = BestWithCommunityWild(jokertest.Cards("7h", "7d", "9c", "Ts", "2d"), SevenHearts)
+	if h.Ranking() != ThreeOfAKind || h.Description() != "three of a kind sevens" {
+		t.Fatalf("BestWithCommunityWild() = %v; want three of a kind sevens", h)
+	}
+}